@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// Authentication plugins being phased out of MySQL, with the versions they were deprecated in,
+// disabled by default in and removed in. Accounts using them can be moved to caching_sha2_password
+// in place by changing auth_plugin.
+type authPluginLifecycle struct {
+	Deprecated string
+	Disabled   string
+	Removed    string
+}
+
+var kAuthPluginLifecycles = map[string]authPluginLifecycle{
+	"mysql_old_password":    {Deprecated: "5.6.5", Removed: "5.7.5"},
+	"mysql_native_password": {Deprecated: "8.0.34", Disabled: "8.4.0", Removed: "9.0.0"},
+	"sha256_password":       {Deprecated: "8.0.16"},
+}
+
+// authPluginWarning returns why the plugin shouldn't be used on a MySQL server of the version, or
+// an empty string if it's fine.
+func authPluginWarning(serverVersion *version.Version, plugin string) string {
+	lifecycle, ok := kAuthPluginLifecycles[plugin]
+	if !ok {
+		return ""
+	}
+	// MariaDB and TiDB have their own plugins and versions, e.g. 10.11.2-MariaDB or
+	// 8.0.11-TiDB-v7.5.0, so they aren't checked.
+	if flavor := serverVersion.Prerelease(); strings.Contains(flavor, "MariaDB") || strings.Contains(flavor, "TiDB") {
+		return ""
+	}
+	reached := func(v string) bool {
+		if v == "" {
+			return false
+		}
+		ver, _ := version.NewVersion(v)
+		return serverVersion.GreaterThanOrEqual(ver)
+	}
+
+	switch {
+	case reached(lifecycle.Removed):
+		return fmt.Sprintf("%s was removed in MySQL %s, accounts using it can't log in", plugin, lifecycle.Removed)
+	case reached(lifecycle.Disabled):
+		return fmt.Sprintf("%s is disabled by default since MySQL %s, accounts using it can't log in unless it's enabled", plugin, lifecycle.Disabled)
+	case reached(lifecycle.Deprecated):
+		return fmt.Sprintf("%s is deprecated since MySQL %s", plugin, lifecycle.Deprecated)
+	}
+	return ""
+}
+
+// authPluginDiagnostics warns about an account using a plugin that is deprecated on the server.
+// It only uses the version cached with the connection, so it doesn't cost a query on refresh.
+func authPluginDiagnostics(ctx context.Context, meta interface{}, user, host, plugin string) diag.Diagnostics {
+	warning := authPluginWarning(getVersionFromMeta(ctx, meta), plugin)
+	if warning == "" {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("User %s@%s uses a deprecated authentication plugin", user, host),
+		Detail:   warning + ". Set auth_plugin to caching_sha2_password to change it in place.",
+	}}
+}
@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestAuthPluginWarning(t *testing.T) {
+	tests := []struct {
+		version  string
+		plugin   string
+		expected string
+	}{
+		{"8.0.33", "mysql_native_password", ""},
+		{"8.0.34", "mysql_native_password", "deprecated since MySQL 8.0.34"},
+		{"8.4.2", "mysql_native_password", "disabled by default since MySQL 8.4.0"},
+		{"9.1.0", "mysql_native_password", "removed in MySQL 9.0.0"},
+		{"8.0.20", "sha256_password", "deprecated since MySQL 8.0.16"},
+		{"9.1.0", "caching_sha2_password", ""},
+		{"9.1.0", "auth_socket", ""},
+		{"10.11.2-MariaDB", "mysql_native_password", ""},
+		{"8.0.11-TiDB-v7.5.0", "mysql_native_password", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.plugin, func(t *testing.T) {
+			warning := authPluginWarning(version.Must(version.NewVersion(tt.version)), tt.plugin)
+			if (tt.expected == "") != (warning == "") || !strings.Contains(warning, tt.expected) {
+				t.Errorf("authPluginWarning returned %q, expected %q", warning, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceDiscovery enumerates the objects of an existing server with the IDs their resources
+// are imported with, so they can be adopted with import blocks using for_each.
+func dataSourceDiscovery() *schema.Resource {
+	importID := &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	computedString := &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+	return &schema.Resource{
+		ReadContext: ReadDiscovery,
+		Schema: map[string]*schema.Schema{
+			"user_pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"include_system": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user":      computedString,
+						"host":      computedString,
+						"import_id": importID,
+					},
+				},
+			},
+			"roles": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":      computedString,
+						"host":      computedString,
+						"import_id": importID,
+					},
+				},
+			},
+			"databases": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":      computedString,
+						"import_id": importID,
+					},
+				},
+			},
+			"grants": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user":     computedString,
+						"host":     computedString,
+						"database": computedString,
+						"table":    computedString,
+						"privileges": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"roles": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"grant": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"import_id": importID,
+					},
+				},
+			},
+			"global_variables": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":      computedString,
+						"value":     computedString,
+						"import_id": importID,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadDiscovery(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	filter := discoveryFilter{
+		UserPattern:   d.Get("user_pattern").(string),
+		IncludeSystem: d.Get("include_system").(bool),
+	}
+	hasRoles := hasRoleTables(getVersionFromMeta(ctx, meta))
+
+	roles := []UserOrRole{}
+	if hasRoles {
+		if roles, err = listRoles(ctx, db, filter); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	users, err := listUsers(ctx, db, filter, hasRoles)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	databases, err := listDatabases(ctx, db, filter)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grants := []map[string]interface{}{}
+	for _, account := range append(append([]UserOrRole{}, roles...), users...) {
+		accountGrants, err := listGrants(ctx, db, account)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, grant := range accountGrants {
+			grants = append(grants, flattenDiscoveredGrant(grant))
+		}
+	}
+
+	variables := map[string]string{}
+	if hasRoles {
+		if variables, err = listGlobalVariables(ctx, db); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := d.Set("users", flattenDiscoveredUsers(users)); err != nil {
+		return diag.Errorf("failed setting users field: %v", err)
+	}
+	if err := d.Set("roles", flattenDiscoveredRoles(roles)); err != nil {
+		return diag.Errorf("failed setting roles field: %v", err)
+	}
+	if err := d.Set("databases", flattenDiscoveredDatabases(databases)); err != nil {
+		return diag.Errorf("failed setting databases field: %v", err)
+	}
+	if err := d.Set("grants", grants); err != nil {
+		return diag.Errorf("failed setting grants field: %v", err)
+	}
+	if err := d.Set("global_variables", flattenDiscoveredVariables(variables)); err != nil {
+		return diag.Errorf("failed setting global_variables field: %v", err)
+	}
+
+	d.SetId(id.UniqueId())
+	return nil
+}
+
+func flattenDiscoveredUsers(users []UserOrRole) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(users))
+	for i, user := range users {
+		result[i] = map[string]interface{}{
+			"user":      user.Name,
+			"host":      user.Host,
+			"import_id": formatUserHostId(user.Name, user.Host),
+		}
+	}
+	return result
+}
+
+func flattenDiscoveredRoles(roles []UserOrRole) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(roles))
+	for i, role := range roles {
+		result[i] = map[string]interface{}{
+			"name":      role.Name,
+			"host":      role.Host,
+			"import_id": formatRoleReference(role),
+		}
+	}
+	return result
+}
+
+func flattenDiscoveredDatabases(databases []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(databases))
+	for i, database := range databases {
+		result[i] = map[string]interface{}{
+			"name":      database,
+			"import_id": database,
+		}
+	}
+	return result
+}
+
+func flattenDiscoveredVariables(variables map[string]string) []map[string]interface{} {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		result[i] = map[string]interface{}{
+			"name":      name,
+			"value":     variables[name],
+			"import_id": name,
+		}
+	}
+	return result
+}
+
+// flattenDiscoveredGrant returns the grant with the attributes of mysql_grant.
+func flattenDiscoveredGrant(grant MySQLGrant) map[string]interface{} {
+	account := grant.GetUserOrRole()
+	result := map[string]interface{}{
+		"user":       account.Name,
+		"host":       account.Host,
+		"database":   "",
+		"table":      "",
+		"privileges": []string{},
+		"roles":      []string{},
+		"grant":      grant.GrantOption(),
+		"import_id":  grantImportID(grant),
+	}
+	switch typed := grant.(type) {
+	case *TablePrivilegeGrant:
+		result["database"] = typed.Database
+		result["table"] = typed.Table
+		result["privileges"] = normalizePerms(typed.Privileges)
+	case *ProcedurePrivilegeGrant:
+		result["database"] = fmt.Sprintf("%s %s.%s", typed.ObjectT, typed.Database, typed.CallableName)
+		result["privileges"] = normalizePerms(typed.Privileges)
+	case *RoleGrant:
+		roles := append([]string{}, typed.Roles...)
+		sort.Strings(roles)
+		result["roles"] = roles
+	}
+	return result
+}
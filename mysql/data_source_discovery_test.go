@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDiscovery(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_database" "test" {
+  name = "tf-test-discovery"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-discovery"
+  host = "%"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.test.user
+  host       = mysql_user.test.host
+  database   = mysql_database.test.name
+  privileges = ["SELECT"]
+}
+
+data "mysql_discovery" "test" {
+  user_pattern = "jdoe-discovery"
+
+  depends_on = [mysql_grant.test]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.mysql_discovery.test", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_discovery.test", "users.0.import_id", "jdoe-discovery@%"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.mysql_discovery.test", "databases.*", map[string]string{
+						"import_id": "tf-test-discovery",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.mysql_discovery.test", "grants.*", map[string]string{
+						"database":  "tf-test-discovery",
+						"import_id": "jdoe-discovery@%@tf-test-discovery@*",
+					}),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceUsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ShowUsers,
+		Schema: map[string]*schema.Schema{
+			"pattern": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"attributes": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"comment": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"attributes": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// matchUserAttributes tells whether the attributes contain all the wanted ones. The comment can be
+// matched under its "comment" key.
+func matchUserAttributes(comment string, attributes map[string]string, wanted map[string]interface{}) bool {
+	for key, value := range wanted {
+		actual, ok := attributes[key]
+		if key == userCommentAttribute {
+			actual, ok = comment, comment != ""
+		}
+		if !ok || actual != value.(string) {
+			return false
+		}
+	}
+	return true
+}
+
+func ShowUsers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pattern := d.Get("pattern").(string)
+	wanted := d.Get("attributes").(map[string]interface{})
+
+	sql := "SELECT USER, HOST, ATTRIBUTE FROM information_schema.USER_ATTRIBUTES"
+	args := []interface{}{}
+	if pattern != "" {
+		sql += " WHERE USER LIKE ?"
+		args = append(args, pattern)
+	}
+	sql += " ORDER BY USER, HOST"
+
+	log.Printf("[DEBUG] SQL: %s", sql)
+
+	rows, err := db.QueryContext(ctx, sql, args...)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownTableErrCode {
+			return diag.Errorf("user attributes are not supported by the server, MySQL 8.0.21 or newer is required")
+		}
+		return diag.Errorf("failed querying for users: %v", err)
+	}
+	defer rows.Close()
+
+	users := []map[string]interface{}{}
+	for rows.Next() {
+		var user, host string
+		var attributeJSON *string
+
+		if err := rows.Scan(&user, &host, &attributeJSON); err != nil {
+			return diag.Errorf("failed scanning MySQL rows: %v", err)
+		}
+
+		var comment string
+		attributes := map[string]string{}
+		if attributeJSON != nil {
+			comment, attributes, err = splitUserAttributes(*attributeJSON)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if !matchUserAttributes(comment, attributes, wanted) {
+			continue
+		}
+
+		users = append(users, map[string]interface{}{
+			"user":       user,
+			"host":       host,
+			"comment":    comment,
+			"attributes": attributes,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.Errorf("failed reading users: %v", err)
+	}
+
+	if err := d.Set("users", users); err != nil {
+		return diag.Errorf("failed setting users field: %v", err)
+	}
+
+	d.SetId(id.UniqueId())
+
+	return nil
+}
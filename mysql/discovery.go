@@ -0,0 +1,281 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Objects that come with the server and should never be adopted into Terraform
+// when enumerating an existing installation.
+var (
+	systemDatabases = map[string]bool{
+		"information_schema": true,
+		"mysql":              true,
+		"performance_schema": true,
+		"sys":                true,
+		"metrics_schema":     true, // TiDB
+	}
+
+	systemUsers = map[string]bool{
+		"root":              true,
+		"mariadb.sys":       true,
+		"rdsadmin":          true, // Amazon RDS
+		"rdsrepladmin":      true,
+		"azure_superuser":   true, // Azure Database for MySQL
+		"cloudsqlsuperuser": true, // Cloud SQL
+		"cloudsqlimport":    true,
+		"cloudsqlexport":    true,
+	}
+)
+
+// discoveryFilter narrows what the enumeration helpers return.
+type discoveryFilter struct {
+	// UserPattern is a LIKE pattern users and roles must match. Empty matches all.
+	UserPattern string
+	// IncludeSystem also returns built-in accounts and schemas.
+	IncludeSystem bool
+}
+
+// hasRoleTables tells whether the server has mysql.role_edges and performance_schema.variables_info,
+// which listRoles and listGlobalVariables need. MariaDB and TiDB report versions of their own.
+func hasRoleTables(serverVersion *version.Version) bool {
+	requiredVersion, _ := version.NewVersion("8.0.0")
+	flavor := serverVersion.Prerelease()
+	return serverVersion.GreaterThanOrEqual(requiredVersion) && !strings.Contains(flavor, "MariaDB") && !strings.Contains(flavor, "TiDB")
+}
+
+func isSystemDatabase(name string) bool {
+	return systemDatabases[strings.ToLower(name)]
+}
+
+func isSystemUser(name string) bool {
+	return systemUsers[name] || strings.HasPrefix(name, "mysql.")
+}
+
+// listAccounts returns every account in mysql.user matching the filter.
+// Roles are accounts too, so callers wanting only users should subtract listRoles.
+func listAccounts(ctx context.Context, db *sql.DB, filter discoveryFilter) ([]UserOrRole, error) {
+	stmtSQL := "SELECT User, Host FROM mysql.user"
+	var args []interface{}
+	if filter.UserPattern != "" {
+		stmtSQL += " WHERE User LIKE ?"
+		args = append(args, filter.UserPattern)
+	}
+	stmtSQL += " ORDER BY User, Host"
+
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing accounts: %w", err)
+	}
+	defer rows.Close()
+	return scanAccounts(rows, filter)
+}
+
+// discoveryRows is the part of *sql.Rows the scanning helpers use.
+type discoveryRows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// scanAccounts reads user and host rows, leaving out the accounts the filter doesn't match.
+func scanAccounts(rows discoveryRows, filter discoveryFilter) ([]UserOrRole, error) {
+	accounts := []UserOrRole{}
+	for rows.Next() {
+		var account UserOrRole
+		if err := rows.Scan(&account.Name, &account.Host); err != nil {
+			return nil, fmt.Errorf("failed scanning account: %w", err)
+		}
+		if !filter.IncludeSystem && isSystemUser(account.Name) {
+			continue
+		}
+		if filter.UserPattern != "" && !matchLikePattern(filter.UserPattern, account.Name) {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// listRoles returns accounts used as roles, i.e. granted to someone or set as a default role.
+// MySQL doesn't distinguish roles from users otherwise, so a role nobody uses is reported as a user.
+func listRoles(ctx context.Context, db *sql.DB, filter discoveryFilter) ([]UserOrRole, error) {
+	stmtSQL := "SELECT FROM_USER, FROM_HOST FROM mysql.role_edges" +
+		" UNION SELECT DEFAULT_ROLE_USER, DEFAULT_ROLE_HOST FROM mysql.default_roles"
+
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles, err := scanAccounts(rows, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].IDString() < roles[j].IDString()
+	})
+	return roles, nil
+}
+
+// listUsers returns accounts that are not roles. Servers without roles (before MySQL 8.0)
+// have no mysql.role_edges table, so hasRoles must be false for them.
+func listUsers(ctx context.Context, db *sql.DB, filter discoveryFilter, hasRoles bool) ([]UserOrRole, error) {
+	accounts, err := listAccounts(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRoles {
+		return accounts, nil
+	}
+
+	roles, err := listRoles(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	isRole := map[UserOrRole]bool{}
+	for _, role := range roles {
+		isRole[role] = true
+	}
+
+	users := []UserOrRole{}
+	for _, account := range accounts {
+		if !isRole[account] {
+			users = append(users, account)
+		}
+	}
+	return users, nil
+}
+
+// listDatabases returns schema names, without system schemas unless requested.
+func listDatabases(ctx context.Context, db *sql.DB, filter discoveryFilter) ([]string, error) {
+	stmtSQL := "SHOW DATABASES"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing databases: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDatabases(rows, filter)
+}
+
+func scanDatabases(rows discoveryRows, filter discoveryFilter) ([]string, error) {
+	databases := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed scanning database: %w", err)
+		}
+		if !filter.IncludeSystem && isSystemDatabase(name) {
+			continue
+		}
+		databases = append(databases, name)
+	}
+	return databases, rows.Err()
+}
+
+// listGlobalVariables returns global variables whose value doesn't come from the compiled-in default.
+// It relies on performance_schema.variables_info, which is only present in MySQL 8.0 and newer.
+func listGlobalVariables(ctx context.Context, db *sql.DB) (map[string]string, error) {
+	stmtSQL := "SELECT g.VARIABLE_NAME, g.VARIABLE_VALUE FROM performance_schema.global_variables g" +
+		" JOIN performance_schema.variables_info i ON i.VARIABLE_NAME = g.VARIABLE_NAME" +
+		" WHERE i.VARIABLE_SOURCE IN ('DYNAMIC', 'PERSISTED')"
+
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+	rows, err := db.QueryContext(ctx, stmtSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing global variables: %w", err)
+	}
+	defer rows.Close()
+
+	return scanGlobalVariables(rows)
+}
+
+func scanGlobalVariables(rows discoveryRows) (map[string]string, error) {
+	variables := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed scanning global variable: %w", err)
+		}
+		variables[name] = value
+	}
+	return variables, rows.Err()
+}
+
+// listGrants returns the grants of the account, with rows for the same object merged the same way ReadGrant does.
+func listGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+	allGrants, err := showUserGrants(ctx, db, userOrRole)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := []MySQLGrant{}
+	for _, grant := range allGrants {
+		combined := false
+		for i, existing := range merged {
+			if existing.ConflictsWithGrant(grant) {
+				merged[i], err = combineGrants(existing, grant)
+				if err != nil {
+					return nil, fmt.Errorf("failed to combine grants for %s: %w", userOrRole.IDString(), err)
+				}
+				combined = true
+				break
+			}
+		}
+		if !combined {
+			merged = append(merged, grant)
+		}
+	}
+	return merged, nil
+}
+
+// matchLikePattern evaluates a SQL LIKE pattern (with % and _ wildcards and \ escapes) client-side.
+func matchLikePattern(pattern, value string) bool {
+	p := []rune(pattern)
+	v := []rune(value)
+	var match func(pi, vi int) bool
+	match = func(pi, vi int) bool {
+		for pi < len(p) {
+			switch p[pi] {
+			case '%':
+				for k := vi; k <= len(v); k++ {
+					if match(pi+1, k) {
+						return true
+					}
+				}
+				return false
+			case '_':
+				if vi >= len(v) {
+					return false
+				}
+			case '\\':
+				if pi+1 < len(p) {
+					pi++
+				}
+				if vi >= len(v) || v[vi] != p[pi] {
+					return false
+				}
+			default:
+				if vi >= len(v) || v[vi] != p[pi] {
+					return false
+				}
+			}
+			pi++
+			vi++
+		}
+		return vi == len(v)
+	}
+	return match(0, 0)
+}
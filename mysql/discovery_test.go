@@ -0,0 +1,161 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+)
+
+func TestMatchLikePattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"", "", true},
+		{"", "app", false},
+		{"%", "", true},
+		{"%", "app", true},
+		{"app", "app", true},
+		{"app", "App", false},
+		{"app%", "app_reader", true},
+		{"app%", "myapp", false},
+		{"%app%", "myapp_reader", true},
+		{"a_p", "app", true},
+		{"a_p", "ap", false},
+		{"app\\_%", "app_reader", true},
+		{"app\\_%", "appreader", false},
+		{"100\\%", "100%", true},
+		{"100\\%", "1000", false},
+		{"%.%", "mysql.sys", true},
+		{"j%e", "jdoe", true},
+		{"j%e", "jdoes", false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s~%s", tt.pattern, tt.value), func(t *testing.T) {
+			if matched := matchLikePattern(tt.pattern, tt.value); matched != tt.expected {
+				t.Errorf("matchLikePattern(%q, %q) = %v, expected %v", tt.pattern, tt.value, matched, tt.expected)
+			}
+		})
+	}
+}
+
+// fakeRows returns the rows given as strings to the scanning helpers.
+type fakeRows struct {
+	rows [][]string
+	next int
+	err  error
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.rows[r.next-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("expected %d columns, got %d", len(row), len(dest))
+	}
+	for i, value := range row {
+		*dest[i].(*string) = value
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func TestScanAccounts(t *testing.T) {
+	rows := [][]string{
+		{"app", "%"},
+		{"app_reader", "10.%"},
+		{"root", "localhost"},
+		{"mysql.sys", "localhost"},
+		{"rdsadmin", "localhost"},
+	}
+	tests := []struct {
+		filter   discoveryFilter
+		expected []UserOrRole
+	}{
+		{discoveryFilter{}, []UserOrRole{{"app", "%"}, {"app_reader", "10.%"}}},
+		{discoveryFilter{UserPattern: "app\\_%"}, []UserOrRole{{"app_reader", "10.%"}}},
+		{discoveryFilter{UserPattern: "r%", IncludeSystem: true}, []UserOrRole{{"root", "localhost"}, {"rdsadmin", "localhost"}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%+v", tt.filter), func(t *testing.T) {
+			accounts, err := scanAccounts(&fakeRows{rows: rows}, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(accounts, tt.expected) {
+				t.Errorf("scanAccounts returned %v, expected %v", accounts, tt.expected)
+			}
+		})
+	}
+
+	if _, err := scanAccounts(&fakeRows{err: errors.New("broken")}, discoveryFilter{}); err == nil {
+		t.Errorf("expected the error of the rows")
+	}
+}
+
+func TestScanDatabases(t *testing.T) {
+	rows := [][]string{{"app"}, {"information_schema"}, {"mysql"}, {"Performance_Schema"}, {"sys"}}
+	databases, err := scanDatabases(&fakeRows{rows: rows}, discoveryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(databases, []string{"app"}) {
+		t.Errorf("scanDatabases returned %v", databases)
+	}
+
+	databases, err = scanDatabases(&fakeRows{rows: rows}, discoveryFilter{IncludeSystem: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(databases) != len(rows) {
+		t.Errorf("scanDatabases returned %v with system schemas", databases)
+	}
+}
+
+func TestScanGlobalVariables(t *testing.T) {
+	rows := [][]string{{"max_connections", "500"}, {"sql_mode", ""}}
+	variables, err := scanGlobalVariables(&fakeRows{rows: rows})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(variables, map[string]string{"max_connections": "500", "sql_mode": ""}) {
+		t.Errorf("scanGlobalVariables returned %v", variables)
+	}
+}
+
+func TestHasRoleTables(t *testing.T) {
+	for v, expected := range map[string]bool{
+		"5.7.44":             false,
+		"8.0.36":             true,
+		"9.1.0":              true,
+		"10.11.2-MariaDB":    false,
+		"8.0.11-TiDB-v7.5.0": false,
+	} {
+		if hasRoles := hasRoleTables(version.Must(version.NewVersion(v))); hasRoles != expected {
+			t.Errorf("hasRoleTables(%s) = %v, expected %v", v, hasRoles, expected)
+		}
+	}
+}
+
+func TestFlattenDiscoveredGrant(t *testing.T) {
+	account := UserOrRole{Name: "app", Host: "%"}
+	grant := flattenDiscoveredGrant(&TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"select", "INSERT"}, Grant: true, UserOrRole: account})
+	if grant["database"] != "app" || grant["table"] != "*" || grant["grant"] != true || grant["import_id"] != "app@%@app@*@" {
+		t.Errorf("unexpected table grant %v", grant)
+	}
+
+	grant = flattenDiscoveredGrant(&RoleGrant{Roles: []string{"writer", "reader"}, UserOrRole: account})
+	if !reflect.DeepEqual(grant["roles"], []string{"reader", "writer"}) || grant["import_id"] != "app@%@*@*;r" {
+		t.Errorf("unexpected role grant %v", grant)
+	}
+}
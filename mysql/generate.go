@@ -0,0 +1,556 @@
+package mysql
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// RunGenerate implements the `generate` subcommand, which connects to an existing server and writes
+// Terraform configuration with import blocks for the objects found there.
+func RunGenerate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dsn := flags.String("dsn", os.Getenv("MYSQL_DSN"), "DSN of the server, e.g. root:secret@tcp(127.0.0.1:3306)/ (defaults to $MYSQL_DSN)")
+	out := flags.String("out", "", "file to write the configuration to (defaults to stdout)")
+	userPattern := flags.String("user-pattern", "", "only include users and roles matching this LIKE pattern")
+	includeSystem := flags.Bool("include-system", false, "include built-in accounts and system schemas")
+	forEachMin := flags.Int("for-each-min", 3, "grants of one account differing only in database and table are combined with for_each from this many on (0 disables)")
+	verbose := flags.Bool("verbose", false, "log queries to stderr")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *dsn == "" {
+		fmt.Fprintln(stderr, "generate: -dsn is required")
+		flags.Usage()
+		return 2
+	}
+
+	if *verbose {
+		log.SetOutput(stderr)
+	} else {
+		log.SetOutput(io.Discard)
+	}
+
+	conf, err := mysql.ParseDSN(*dsn)
+	if err != nil {
+		fmt.Fprintf(stderr, "generate: invalid DSN: %v\n", err)
+		return 2
+	}
+	conf.InterpolateParams = true
+
+	g := &generator{
+		meta: &MySQLConfiguration{
+			Config:                 conf,
+			ConnectRetryTimeoutSec: 10 * time.Second,
+		},
+		filter: discoveryFilter{
+			UserPattern:   *userPattern,
+			IncludeSystem: *includeSystem,
+		},
+		forEachMin: *forEachMin,
+		names:      map[string]int{},
+	}
+
+	resources, err := g.collect(context.Background())
+	if err != nil {
+		fmt.Fprintf(stderr, "generate: %v\n", err)
+		return 1
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "generate: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.WriteString(w, renderResources(resources)); err != nil {
+		fmt.Fprintf(stderr, "generate: failed writing configuration: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// hclExpr is emitted verbatim instead of as a quoted string.
+type hclExpr string
+
+type hclAttr struct {
+	Key   string
+	Value interface{} // string, bool, []string or hclExpr
+}
+
+// hclBlock is a nested block of a resource.
+type hclBlock struct {
+	Type  string
+	Attrs []hclAttr
+}
+
+type forEachEntry struct {
+	Key      string
+	Values   []hclAttr
+	ImportID string
+}
+
+type generatedResource struct {
+	Type     string
+	Name     string
+	Comment  string
+	Attrs    []hclAttr
+	Blocks   []hclBlock
+	ImportID string
+	// ForEach, when set, replaces ImportID with one import per entry.
+	ForEach []forEachEntry
+}
+
+type generator struct {
+	meta       *MySQLConfiguration
+	filter     discoveryFilter
+	forEachMin int
+	names      map[string]int
+}
+
+func (g *generator) collect(ctx context.Context) ([]generatedResource, error) {
+	oneConnection, err := connectToMySQLInternal(ctx, g.meta)
+	if err != nil {
+		return nil, err
+	}
+	db := oneConnection.Db
+	hasRoles := hasRoleTables(oneConnection.Version)
+
+	var resources []generatedResource
+
+	var roles []UserOrRole
+	if hasRoles {
+		roles, err = listRoles(ctx, db, g.filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			resources = append(resources, generatedResource{
+				Type:     "mysql_role",
+				Name:     g.name("role", role.Name, role.Host),
+				Attrs:    []hclAttr{{"name", role.Name}, {"host", role.Host}},
+				ImportID: formatRoleReference(role),
+			})
+		}
+	}
+
+	users, err := listUsers(ctx, db, g.filter, hasRoles)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		res, err := g.userResource(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+
+	if hasRoles {
+		for _, user := range users {
+			res, ok, err := g.defaultRolesResource(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				resources = append(resources, res)
+			}
+		}
+	}
+
+	databases, err := listDatabases(ctx, db, g.filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, database := range databases {
+		res, err := g.databaseResource(ctx, database)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+
+	for _, account := range append(roles, users...) {
+		grants, err := listGrants(ctx, db, account)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, g.grantResources(account, grants)...)
+	}
+
+	if hasRoles {
+		variables, err := listGlobalVariables(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(variables))
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			resources = append(resources, generatedResource{
+				Type:     "mysql_global_variable",
+				Name:     g.name("variable", name),
+				Attrs:    []hclAttr{{"name", name}, {"value", variables[name]}},
+				ImportID: name,
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+func (g *generator) userResource(ctx context.Context, user UserOrRole) (generatedResource, error) {
+	d := resourceUser().Data(nil)
+	d.SetId(formatUserHostId(user.Name, user.Host))
+	d.Set("user", user.Name)
+	d.Set("host", user.Host)
+	if diags := ReadUser(ctx, d, g.meta); diags.HasError() {
+		return generatedResource{}, fmt.Errorf("failed reading user %s: %v", user.IDString(), diags)
+	}
+
+	attrs := []hclAttr{{"user", user.Name}, {"host", user.Host}}
+	comment := ""
+	if plugin := d.Get("auth_plugin").(string); plugin != "" {
+		attrs = append(attrs, hclAttr{"auth_plugin", plugin})
+	}
+	if hex := d.Get("auth_string_hex").(string); hex != "" {
+		attrs = append(attrs, hclAttr{"auth_string_hex", hex})
+	} else if hashed := d.Get("auth_string_hashed").(string); hashed != "" {
+		attrs = append(attrs, hclAttr{"auth_string_hashed", hashed})
+	} else {
+		comment = "The password can't be read from the server; set plaintext_password to manage it."
+	}
+	var blocks []hclBlock
+	if requirements, length := parseTLSRequirements(d.Get("tls_option").(string)); length > 0 && requirements.Type != "NONE" {
+		blocks = append(blocks, tlsRequirementsBlock(requirements))
+	}
+
+	return generatedResource{
+		Type:     "mysql_user",
+		Name:     g.name("user", user.Name, user.Host),
+		Comment:  comment,
+		Attrs:    attrs,
+		Blocks:   blocks,
+		ImportID: d.Id(),
+	}, nil
+}
+
+// tlsRequirementsBlock returns the tls_requirements block of the requirements read from the server.
+func tlsRequirementsBlock(requirements tlsRequirements) hclBlock {
+	if !requirements.specified() {
+		return hclBlock{Type: "tls_requirements", Attrs: []hclAttr{{"type", requirements.Type}}}
+	}
+	var attrs []hclAttr
+	for _, option := range [][2]string{{"subject", requirements.Subject}, {"issuer", requirements.Issuer}, {"cipher", requirements.Cipher}} {
+		if option[1] != "" {
+			attrs = append(attrs, hclAttr{option[0], option[1]})
+		}
+	}
+	return hclBlock{Type: "tls_requirements", Attrs: attrs}
+}
+
+func (g *generator) defaultRolesResource(ctx context.Context, user UserOrRole) (generatedResource, bool, error) {
+	d := resourceDefaultRoles().Data(nil)
+	d.SetId(formatUserHostId(user.Name, user.Host))
+	d.Set("user", user.Name)
+	d.Set("host", user.Host)
+	if diags := ReadDefaultRoles(ctx, d, g.meta); diags.HasError() {
+		return generatedResource{}, false, fmt.Errorf("failed reading default roles of %s: %v", user.IDString(), diags)
+	}
+
+	roles := setToArray(d.Get("roles"))
+	if len(roles) == 0 {
+		return generatedResource{}, false, nil
+	}
+	sort.Strings(roles)
+
+	return generatedResource{
+		Type:     "mysql_default_roles",
+		Name:     g.name("default_roles", user.Name, user.Host),
+		Attrs:    []hclAttr{{"user", user.Name}, {"host", user.Host}, {"roles", roles}},
+		ImportID: d.Id(),
+	}, true, nil
+}
+
+func (g *generator) databaseResource(ctx context.Context, name string) (generatedResource, error) {
+	d := resourceDatabase().Data(nil)
+	d.SetId(name)
+	if diags := ReadDatabase(ctx, d, g.meta); diags.HasError() {
+		return generatedResource{}, fmt.Errorf("failed reading database %s: %v", name, diags)
+	}
+
+	return generatedResource{
+		Type: "mysql_database",
+		Name: g.name("database", name),
+		Attrs: []hclAttr{
+			{"name", name},
+			{"default_character_set", d.Get("default_character_set").(string)},
+			{"default_collation", d.Get("default_collation").(string)},
+		},
+		ImportID: name,
+	}, nil
+}
+
+// grantResources turns the merged grants of one account into resources. Table grants that only differ
+// in database and table are combined into a single resource using for_each.
+func (g *generator) grantResources(account UserOrRole, grants []MySQLGrant) []generatedResource {
+	type tableGroup struct {
+		privileges []string
+		grant      bool
+		grants     []*TablePrivilegeGrant
+	}
+	var groups []*tableGroup
+	groupByKey := map[string]*tableGroup{}
+
+	var resources []generatedResource
+	for _, grant := range grants {
+		if tableGrant, ok := grant.(*TablePrivilegeGrant); ok && len(tableGrant.RevokedDatabases) == 0 {
+			privileges := normalizePerms(tableGrant.Privileges)
+			key := fmt.Sprintf("%s|%t", strings.Join(privileges, ","), tableGrant.Grant)
+			group := groupByKey[key]
+			if group == nil {
+				group = &tableGroup{privileges: privileges, grant: tableGrant.Grant}
+				groupByKey[key] = group
+				groups = append(groups, group)
+			}
+			group.grants = append(group.grants, tableGrant)
+			continue
+		}
+		resources = append(resources, g.grantResource(account, grant))
+	}
+
+	for _, group := range groups {
+		if g.forEachMin <= 0 || len(group.grants) < g.forEachMin {
+			for _, grant := range group.grants {
+				resources = append(resources, g.grantResource(account, grant))
+			}
+			continue
+		}
+
+		attrs := grantAccountAttrs(account)
+		attrs = append(attrs,
+			hclAttr{"database", hclExpr("each.value.database")},
+			hclAttr{"table", hclExpr("each.value.table")},
+			hclAttr{"privileges", group.privileges},
+		)
+		if group.grant {
+			attrs = append(attrs, hclAttr{"grant", true})
+		}
+
+		var entries []forEachEntry
+		for _, grant := range group.grants {
+			entries = append(entries, forEachEntry{
+				Key:      fmt.Sprintf("%s.%s", grant.Database, grant.Table),
+				Values:   []hclAttr{{"database", grant.Database}, {"table", grant.Table}},
+				ImportID: grantImportID(grant),
+			})
+		}
+		resources = append(resources, generatedResource{
+			Type:    "mysql_grant",
+			Name:    g.name(append([]string{account.Name}, group.privileges...)...),
+			Attrs:   attrs,
+			ForEach: entries,
+		})
+	}
+
+	return resources
+}
+
+func (g *generator) grantResource(account UserOrRole, grant MySQLGrant) generatedResource {
+	attrs := grantAccountAttrs(account)
+	var nameParts []string
+	switch typed := grant.(type) {
+	case *TablePrivilegeGrant:
+		attrs = append(attrs,
+			hclAttr{"database", typed.Database},
+			hclAttr{"table", typed.Table},
+			hclAttr{"privileges", normalizePerms(typed.Privileges)},
+		)
+		if len(typed.RevokedDatabases) > 0 {
+			attrs = append(attrs, hclAttr{"revoked_databases", typed.RevokedDatabases})
+		}
+		nameParts = []string{account.Name, typed.Database, typed.Table}
+	case *ProcedurePrivilegeGrant:
+		attrs = append(attrs,
+			hclAttr{"database", fmt.Sprintf("%s %s.%s", typed.ObjectT, typed.Database, typed.CallableName)},
+			hclAttr{"privileges", normalizePerms(typed.Privileges)},
+		)
+		nameParts = []string{account.Name, typed.Database, typed.CallableName}
+	case *RoleGrant:
+		roles := append([]string{}, typed.Roles...)
+		sort.Strings(roles)
+		attrs = append(attrs, hclAttr{"roles", roles})
+		nameParts = []string{account.Name, "roles"}
+	}
+	if grant.GrantOption() {
+		attrs = append(attrs, hclAttr{"grant", true})
+	}
+
+	return generatedResource{
+		Type:     "mysql_grant",
+		Name:     g.name(nameParts...),
+		Attrs:    attrs,
+		ImportID: grantImportID(grant),
+	}
+}
+
+// grantAccountAttrs always uses user and host, as grants are imported that way even for roles.
+func grantAccountAttrs(account UserOrRole) []hclAttr {
+	return []hclAttr{{"user", account.Name}, {"host", account.Host}}
+}
+
+// grantImportID builds an ID in the format accepted by ImportGrant.
+func grantImportID(grant MySQLGrant) string {
+	account := grant.GetUserOrRole()
+	suffix := ""
+	if grant.GrantOption() {
+		suffix = "@"
+	}
+	switch typed := grant.(type) {
+	case *ProcedurePrivilegeGrant:
+		return fmt.Sprintf("%s@%s:%s %s.%s%s", account.Name, account.Host, typed.ObjectT, typed.Database, typed.CallableName, suffix)
+	case *RoleGrant:
+		return fmt.Sprintf("%s@*@*%s;r", formatUserHostId(account.Name, account.Host), suffix)
+	case *TablePrivilegeGrant:
+		return fmt.Sprintf("%s@%s@%s%s", formatUserHostId(account.Name, account.Host), escapeIdPart(typed.Database), escapeIdPart(typed.Table), suffix)
+	}
+	return ""
+}
+
+var kNonIdentifierChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// name builds a unique resource name from the given parts.
+func (g *generator) name(parts ...string) string {
+	var cleaned []string
+	for _, part := range parts {
+		part = strings.Trim(kNonIdentifierChars.ReplaceAllString(strings.ToLower(part), "_"), "_")
+		if part != "" {
+			cleaned = append(cleaned, part)
+		}
+	}
+	name := strings.Join(cleaned, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+
+	g.names[name]++
+	if count := g.names[name]; count > 1 {
+		name = fmt.Sprintf("%s_%d", name, count)
+	}
+	return name
+}
+
+func renderResources(resources []generatedResource) string {
+	var b strings.Builder
+	for i, res := range resources {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if res.Comment != "" {
+			fmt.Fprintf(&b, "# %s\n", res.Comment)
+		}
+		fmt.Fprintf(&b, "resource %s %s {\n", hclString(res.Type), hclString(res.Name))
+		if res.ForEach != nil {
+			b.WriteString("  for_each = {\n")
+			entries := make([]hclAttr, len(res.ForEach))
+			for i, entry := range res.ForEach {
+				entries[i] = hclAttr{hclString(entry.Key), hclExpr(renderObject(entry.Values))}
+			}
+			writeAttrs(&b, "    ", entries)
+			b.WriteString("  }\n\n")
+		}
+		writeAttrs(&b, "  ", res.Attrs)
+		for _, block := range res.Blocks {
+			fmt.Fprintf(&b, "\n  %s {\n", block.Type)
+			writeAttrs(&b, "    ", block.Attrs)
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+
+		address := fmt.Sprintf("%s.%s", res.Type, res.Name)
+		if res.ForEach != nil {
+			for _, entry := range res.ForEach {
+				writeImport(&b, fmt.Sprintf("%s[%s]", address, hclString(entry.Key)), entry.ImportID)
+			}
+		} else {
+			writeImport(&b, address, res.ImportID)
+		}
+	}
+	return b.String()
+}
+
+func writeImport(b *strings.Builder, to, id string) {
+	fmt.Fprintf(b, "\nimport {\n")
+	writeAttrs(b, "  ", []hclAttr{{"to", hclExpr(to)}, {"id", id}})
+	b.WriteString("}\n")
+}
+
+// writeAttrs writes attributes with their equals signs aligned, as terraform fmt does.
+func writeAttrs(b *strings.Builder, indent string, attrs []hclAttr) {
+	width := 0
+	for _, attr := range attrs {
+		if len(attr.Key) > width {
+			width = len(attr.Key)
+		}
+	}
+	for _, attr := range attrs {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, width, attr.Key, hclValue(attr.Value))
+	}
+}
+
+func renderObject(attrs []hclAttr) string {
+	parts := make([]string, len(attrs))
+	for i, attr := range attrs {
+		parts[i] = fmt.Sprintf("%s = %s", attr.Key, hclValue(attr.Value))
+	}
+	return fmt.Sprintf("{ %s }", strings.Join(parts, ", "))
+}
+
+func hclValue(value interface{}) string {
+	switch v := value.(type) {
+	case hclExpr:
+		return string(v)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = hclString(s)
+		}
+		return fmt.Sprintf("[%s]", strings.Join(quoted, ", "))
+	case string:
+		return hclString(v)
+	}
+	panic(fmt.Sprintf("unsupported HCL value %#v", value))
+}
+
+var hclStringReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// hclString quotes s as an HCL string literal, escaping template sequences.
+func hclString(s string) string {
+	return `"` + hclStringReplacer.Replace(s) + `"`
+}
@@ -0,0 +1,134 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateGrantResources(t *testing.T) {
+	account := UserOrRole{Name: "app", Host: "%"}
+	grants := []MySQLGrant{
+		&TablePrivilegeGrant{Database: "a", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: account},
+		&TablePrivilegeGrant{Database: "b", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: account},
+		&TablePrivilegeGrant{Database: "c", Table: "t", Privileges: []string{"SELECT"}, UserOrRole: account},
+		&TablePrivilegeGrant{Database: "d", Table: "*", Privileges: []string{"ALL PRIVILEGES"}, Grant: true, UserOrRole: account},
+		&RoleGrant{Roles: []string{"writer", "reader"}, UserOrRole: account},
+	}
+
+	g := &generator{forEachMin: 3, names: map[string]int{}}
+	resources := g.grantResources(account, grants)
+	if len(resources) != 3 {
+		t.Fatalf("expected 3 resources, got %d: %#v", len(resources), resources)
+	}
+
+	roles := resources[0]
+	if roles.ImportID != "app@%@*@*;r" {
+		t.Errorf("unexpected role grant import ID %q", roles.ImportID)
+	}
+
+	grouped := resources[1]
+	if len(grouped.ForEach) != 3 {
+		t.Fatalf("expected SELECT grants to be grouped, got %#v", grouped)
+	}
+	if grouped.ForEach[2].Key != "c.t" || grouped.ForEach[2].ImportID != "app@%@c@t" {
+		t.Errorf("unexpected for_each entry %#v", grouped.ForEach[2])
+	}
+
+	single := resources[2]
+	if single.ForEach != nil || single.ImportID != "app@%@d@*@" {
+		t.Errorf("expected a single grant with grant option, got %#v", single)
+	}
+
+	g = &generator{forEachMin: 0, names: map[string]int{}}
+	if resources := g.grantResources(account, grants); len(resources) != 5 {
+		t.Errorf("expected no grouping with for-each-min 0, got %d resources", len(resources))
+	}
+}
+
+func TestGenerateRender(t *testing.T) {
+	g := &generator{names: map[string]int{}}
+	resources := []generatedResource{
+		{
+			Type:     "mysql_user",
+			Name:     g.name("user", "jdoe", "%"),
+			Comment:  "Set the password.",
+			Attrs:    []hclAttr{{"user", "jdoe"}, {"host", "%"}, {"auth_plugin", "${x}"}},
+			Blocks:   []hclBlock{tlsRequirementsBlock(tlsRequirements{Subject: "/CN=jdoe", Cipher: "AES256-SHA"})},
+			ImportID: "jdoe@%",
+		},
+		{
+			Type: "mysql_grant",
+			Name: g.name("user", "jdoe", "%"),
+			Attrs: []hclAttr{
+				{"user", "jdoe"},
+				{"database", hclExpr("each.value.database")},
+				{"privileges", []string{"SELECT"}},
+				{"grant", true},
+			},
+			ForEach: []forEachEntry{
+				{Key: "a.*", Values: []hclAttr{{"database", "a"}, {"table", "*"}}, ImportID: "jdoe@%@a@*@"},
+			},
+		},
+	}
+
+	expected := `# Set the password.
+resource "mysql_user" "user_jdoe" {
+  user        = "jdoe"
+  host        = "%"
+  auth_plugin = "$${x}"
+
+  tls_requirements {
+    subject = "/CN=jdoe"
+    cipher  = "AES256-SHA"
+  }
+}
+
+import {
+  to = mysql_user.user_jdoe
+  id = "jdoe@%"
+}
+
+resource "mysql_grant" "user_jdoe_2" {
+  for_each = {
+    "a.*" = { database = "a", table = "*" }
+  }
+
+  user       = "jdoe"
+  database   = each.value.database
+  privileges = ["SELECT"]
+  grant      = true
+}
+
+import {
+  to = mysql_grant.user_jdoe_2["a.*"]
+  id = "jdoe@%@a@*@"
+}
+`
+	if actual := renderResources(resources); actual != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", actual, expected)
+	}
+}
+
+func TestGenerateName(t *testing.T) {
+	g := &generator{names: map[string]int{}}
+	for _, tc := range []struct {
+		parts    []string
+		expected string
+	}{
+		{[]string{"user", "Jane.Doe", "10.0.0.%"}, "user_jane_doe_10_0_0"},
+		{[]string{"1db"}, "_1db"},
+		{[]string{"%"}, "_"},
+		{[]string{"%%"}, "__2"},
+	} {
+		if actual := g.name(tc.parts...); actual != tc.expected {
+			t.Errorf("name(%s) = %q, expected %q", strings.Join(tc.parts, ", "), actual, tc.expected)
+		}
+	}
+}
+
+func TestGenerateTLSRequirementsBlock(t *testing.T) {
+	block := tlsRequirementsBlock(tlsRequirements{Type: "X509"})
+	if block.Type != "tls_requirements" || len(block.Attrs) != 1 || block.Attrs[0] != (hclAttr{"type", "X509"}) {
+		t.Errorf("unexpected block %#v", block)
+	}
+}
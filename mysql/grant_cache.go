@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"slices"
+	"sync"
+)
+
+// The parsed SHOW GRANTS of every account are cached for the rest of the provider run, so refreshing
+// many grants of one user runs a single query. Resources that change grants invalidate the account
+// after their statements ran.
+
+type grantCacheKey struct {
+	db      *sql.DB
+	account string
+}
+
+type grantCacheEntry struct {
+	done   chan struct{}
+	grants []MySQLGrant
+	err    error
+}
+
+type grantCache struct {
+	mtx     sync.Mutex
+	entries map[grantCacheKey]*grantCacheEntry
+}
+
+var userGrantsCache = &grantCache{entries: map[grantCacheKey]*grantCacheEntry{}}
+
+func grantCacheAccount(userOrRole UserOrRole) string {
+	// Roles have no host in the configuration, but SHOW GRANTS reports them with host %.
+	host := userOrRole.Host
+	if host == "" {
+		host = "%"
+	}
+	return userOrRole.Name + "@" + host
+}
+
+// get returns the cached grants of the account, loading them once if needed. Concurrent callers
+// wait for the same load. The grants are copied, since callers modify them.
+func (c *grantCache) get(ctx context.Context, db *sql.DB, userOrRole UserOrRole, load func() ([]MySQLGrant, error)) ([]MySQLGrant, error) {
+	key := grantCacheKey{db: db, account: grantCacheAccount(userOrRole)}
+
+	c.mtx.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &grantCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+	}
+	c.mtx.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else {
+		entry.grants, entry.err = load()
+		close(entry.done)
+		if entry.err != nil {
+			c.mtx.Lock()
+			if c.entries[key] == entry {
+				delete(c.entries, key)
+			}
+			c.mtx.Unlock()
+		}
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return cloneGrants(entry.grants), nil
+}
+
+func (c *grantCache) invalidate(db *sql.DB, userOrRole UserOrRole) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.entries, grantCacheKey{db: db, account: grantCacheAccount(userOrRole)})
+}
+
+func (c *grantCache) invalidateAll(db *sql.DB) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key := range c.entries {
+		if key.db == db {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// invalidateUserGrants has to be called after changing grants of the account.
+func invalidateUserGrants(db *sql.DB, userOrRole UserOrRole) {
+	log.Printf("[DEBUG] Invalidating cached grants of %s", userOrRole.SQLString())
+	userGrantsCache.invalidate(db, userOrRole)
+}
+
+// invalidateAllUserGrants has to be called after changes that may affect grants of any account,
+// like dropping a role that is granted to users.
+func invalidateAllUserGrants(db *sql.DB) {
+	log.Printf("[DEBUG] Invalidating all cached grants")
+	userGrantsCache.invalidateAll(db)
+}
+
+func cloneGrants(grants []MySQLGrant) []MySQLGrant {
+	result := make([]MySQLGrant, 0, len(grants))
+	for _, grant := range grants {
+		switch g := grant.(type) {
+		case *TablePrivilegeGrant:
+			copied := *g
+			copied.Privileges = slices.Clone(g.Privileges)
+			copied.RevokedDatabases = slices.Clone(g.RevokedDatabases)
+			if g.RevokedPrivileges != nil {
+				copied.RevokedPrivileges = map[string][]string{}
+				for database, privileges := range g.RevokedPrivileges {
+					copied.RevokedPrivileges[database] = slices.Clone(privileges)
+				}
+			}
+			copied.TableInclude = slices.Clone(g.TableInclude)
+			copied.TableExclude = slices.Clone(g.TableExclude)
+			result = append(result, &copied)
+		case *ProcedurePrivilegeGrant:
+			copied := *g
+			copied.Privileges = slices.Clone(g.Privileges)
+			result = append(result, &copied)
+		case *RoleGrant:
+			copied := *g
+			copied.Roles = slices.Clone(g.Roles)
+			result = append(result, &copied)
+		default:
+			result = append(result, grant)
+		}
+	}
+	return result
+}
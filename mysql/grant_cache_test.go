@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestGrantCache(t *testing.T) {
+	ctx := context.Background()
+	cache := &grantCache{entries: map[grantCacheKey]*grantCacheEntry{}}
+	jdoe := UserOrRole{Name: "jdoe", Host: "%"}
+	loads := 0
+	load := func() ([]MySQLGrant, error) {
+		loads++
+		return []MySQLGrant{&TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: jdoe}}, nil
+	}
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			grants, err := cache.get(ctx, nil, jdoe, func() ([]MySQLGrant, error) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				return load()
+			})
+			if err != nil || len(grants) != 1 {
+				t.Errorf("unexpected result %v, %v", grants, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Errorf("grants were loaded %d times, expected once", loads)
+	}
+
+	// Callers get copies they can modify.
+	grants, _ := cache.get(ctx, nil, jdoe, load)
+	grants[0].(*TablePrivilegeGrant).AppendPrivileges([]string{"INSERT"})
+	grants, _ = cache.get(ctx, nil, UserOrRole{Name: "jdoe"}, load)
+	if privs := grants[0].(*TablePrivilegeGrant).Privileges; len(privs) != 1 || loads != 1 {
+		t.Errorf("cached grants were modified to %v or reloaded (%d loads)", privs, loads)
+	}
+
+	cache.invalidate(nil, jdoe)
+	if _, err := cache.get(ctx, nil, jdoe, load); err != nil || loads != 2 {
+		t.Errorf("expected a reload after invalidation, got %d loads, %v", loads, err)
+	}
+
+	other := UserOrRole{Name: "other", Host: "localhost"}
+	if _, err := cache.get(ctx, nil, other, func() ([]MySQLGrant, error) { return nil, errors.New("boom") }); err == nil {
+		t.Errorf("expected the load error to be returned")
+	}
+	if _, err := cache.get(ctx, nil, other, load); err != nil || loads != 3 {
+		t.Errorf("expected errors not to be cached, got %d loads, %v", loads, err)
+	}
+
+	cache.invalidateAll(nil)
+	if len(cache.entries) != 0 {
+		t.Errorf("expected no entries after invalidating all, got %d", len(cache.entries))
+	}
+}
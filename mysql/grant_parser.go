@@ -0,0 +1,465 @@
+package mysql
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+// SHOW GRANTS returns GRANT (and with partial revokes also REVOKE) statements. They are tokenized and parsed
+// rather than matched with regular expressions, so that names may contain dots, quotes, @, spaces or escaped
+// wildcards and trailing clauses like IDENTIFIED BY PASSWORD don't get in the way.
+
+type grantTokenKind int
+
+const (
+	grantTokenWord   grantTokenKind = iota // unquoted keyword or name
+	grantTokenIdent                        // `quoted identifier`
+	grantTokenString                       // 'string' or "string"
+	grantTokenPunct                        // any other single character
+)
+
+type grantToken struct {
+	kind  grantTokenKind
+	value string // without quotes and escapes
+	pos   int    // offset in the statement
+}
+
+func (t grantToken) isKeyword(keyword string) bool {
+	return t.kind == grantTokenWord && strings.EqualFold(t.value, keyword)
+}
+
+func (t grantToken) isPunct(punct string) bool {
+	return t.kind == grantTokenPunct && t.value == punct
+}
+
+func isGrantWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '$' || c == '%' || c == '-' || c >= 0x80
+}
+
+func tokenizeGrant(stmt string) ([]grantToken, error) {
+	var tokens []grantToken
+	for i := 0; i < len(stmt); {
+		c := stmt[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '`':
+			value, end, err := readQuotedGrantToken(stmt, i, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, grantToken{kind: grantTokenIdent, value: value, pos: i})
+			i = end
+		case c == '\'' || c == '"':
+			value, end, err := readQuotedGrantToken(stmt, i, true)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, grantToken{kind: grantTokenString, value: value, pos: i})
+			i = end
+		case isGrantWordByte(c):
+			end := i
+			for end < len(stmt) && isGrantWordByte(stmt[end]) {
+				end++
+			}
+			tokens = append(tokens, grantToken{kind: grantTokenWord, value: stmt[i:end], pos: i})
+			i = end
+		default:
+			tokens = append(tokens, grantToken{kind: grantTokenPunct, value: string(c), pos: i})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// readQuotedGrantToken reads a token quoted by stmt[start]. Doubling the quote escapes it; strings also
+// support backslash escapes, where \% and \_ keep the backslash the same way MySQL does.
+func readQuotedGrantToken(stmt string, start int, backslashEscapes bool) (string, int, error) {
+	quote := stmt[start]
+	var b strings.Builder
+	for i := start + 1; i < len(stmt); i++ {
+		c := stmt[i]
+		if c == quote {
+			if i+1 < len(stmt) && stmt[i+1] == quote {
+				b.WriteByte(quote)
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		if backslashEscapes && c == '\\' && i+1 < len(stmt) {
+			i++
+			switch stmt[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case '%', '_':
+				b.WriteByte('\\')
+				b.WriteByte(stmt[i])
+			default:
+				b.WriteByte(stmt[i])
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return "", 0, fmt.Errorf("unterminated %c at offset %d", quote, start)
+}
+
+type grantParser struct {
+	stmt   string
+	tokens []grantToken
+	pos    int
+}
+
+func (p *grantParser) peek() (grantToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return grantToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *grantParser) acceptKeyword(keyword string) bool {
+	if t, ok := p.peek(); ok && t.isKeyword(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *grantParser) acceptPunct(punct string) bool {
+	if t, ok := p.peek(); ok && t.isPunct(punct) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *grantParser) errorf(format string, args ...interface{}) error {
+	at := "end of statement"
+	if t, ok := p.peek(); ok {
+		at = fmt.Sprintf("offset %d", t.pos)
+	}
+	return fmt.Errorf("failed to parse grant statement %s at %s: %s", p.stmt, at, fmt.Sprintf(format, args...))
+}
+
+// isRoleGrant tells GRANT role TO account apart from GRANT privilege ON object TO account.
+func (p *grantParser) isRoleGrant() bool {
+	depth := 0
+	for _, t := range p.tokens[p.pos:] {
+		switch {
+		case t.isPunct("("):
+			depth++
+		case t.isPunct(")"):
+			depth--
+		case depth == 0 && t.isKeyword("ON"):
+			return false
+		case depth == 0 && t.isKeyword("TO"):
+			return true
+		}
+	}
+	return false
+}
+
+// isProxyGrant tells GRANT PROXY ON account TO account apart from other grants.
+func (p *grantParser) isProxyGrant() bool {
+	return len(p.tokens) > p.pos+1 && p.tokens[p.pos].isKeyword("PROXY") && p.tokens[p.pos+1].isKeyword("ON")
+}
+
+// parseName reads a quoted or unquoted name.
+func (p *grantParser) parseName() (string, error) {
+	t, ok := p.peek()
+	if !ok || (t.kind != grantTokenWord && t.kind != grantTokenIdent && t.kind != grantTokenString) {
+		return "", p.errorf("expected a name")
+	}
+	p.pos++
+	return t.value, nil
+}
+
+func (p *grantParser) parseAccount() (UserOrRole, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return UserOrRole{}, err
+	}
+	account := UserOrRole{Name: name}
+	if p.acceptPunct("@") {
+		if account.Host, err = p.parseName(); err != nil {
+			return UserOrRole{}, err
+		}
+	}
+	return account, nil
+}
+
+func (p *grantParser) parsePrivileges() ([]string, error) {
+	var privileges []string
+	for {
+		var words []string
+		for {
+			t, ok := p.peek()
+			if !ok || t.kind != grantTokenWord || t.isKeyword("ON") {
+				break
+			}
+			words = append(words, t.value)
+			p.pos++
+		}
+		if len(words) == 0 {
+			return nil, p.errorf("expected a privilege")
+		}
+		privilege := strings.Join(words, " ")
+
+		if p.acceptPunct("(") {
+			var columns []string
+			for {
+				column, err := p.parseName()
+				if err != nil {
+					return nil, err
+				}
+				columns = append(columns, fmt.Sprintf("`%s`", column))
+				if !p.acceptPunct(",") {
+					break
+				}
+			}
+			if !p.acceptPunct(")") {
+				return nil, p.errorf("expected ) after columns")
+			}
+			privilege = fmt.Sprintf("%s (%s)", privilege, strings.Join(columns, ", "))
+		}
+
+		privileges = append(privileges, privilege)
+		if !p.acceptPunct(",") {
+			return privileges, nil
+		}
+	}
+}
+
+func (p *grantParser) parseObjectName() (string, error) {
+	if p.acceptPunct("*") {
+		return "*", nil
+	}
+	return p.parseName()
+}
+
+// parseTrailingClauses reads REQUIRE and WITH after the grantee, skipping anything else (IDENTIFIED BY, AS ...).
+func (p *grantParser) parseTrailingClauses() (tlsOption string, grantOption bool) {
+	tlsOption = "NONE"
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+		switch {
+		case t.isKeyword("REQUIRE"):
+			start := len(p.stmt)
+			if p.pos < len(p.tokens) {
+				start = p.tokens[p.pos].pos
+			}
+			end := len(p.stmt)
+			for p.pos < len(p.tokens) && !p.tokens[p.pos].isKeyword("WITH") {
+				p.pos++
+			}
+			if p.pos < len(p.tokens) {
+				end = p.tokens[p.pos].pos
+			}
+			if option := strings.TrimSpace(p.stmt[start:end]); option != "" {
+				tlsOption = option
+			}
+		case t.isKeyword("GRANT") || t.isKeyword("ADMIN"):
+			if p.acceptKeyword("OPTION") {
+				grantOption = true
+			}
+		}
+	}
+	return tlsOption, grantOption
+}
+
+func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
+	tokens, err := tokenizeGrant(grantStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tokenize grant statement %s: %w", grantStr, err)
+	}
+	p := &grantParser{stmt: grantStr, tokens: tokens}
+
+	// REVOKE rows are partial revokes, which parsePartialRevokeFromRow reads.
+	if p.acceptKeyword("REVOKE") {
+		return nil, nil
+	}
+	if !p.acceptKeyword("GRANT") {
+		return nil, p.errorf("expected GRANT or REVOKE")
+	}
+
+	// Proxy grants are managed by mysql_proxy_grant, which reads them from mysql.proxies_priv.
+	if p.isProxyGrant() {
+		log.Printf("[DEBUG] Skipping proxy grant %s", grantStr)
+		return nil, nil
+	}
+	if p.isRoleGrant() {
+		return p.parseRoleGrant()
+	}
+
+	on, err := p.parsePrivilegesOnObject()
+	if err != nil {
+		return nil, err
+	}
+	privileges := on.privileges
+
+	if !p.acceptKeyword("TO") {
+		return nil, p.errorf("expected TO")
+	}
+	userOrRole, err := p.parseAccount()
+	if err != nil {
+		return nil, err
+	}
+	tlsOption, grantOption := p.parseTrailingClauses()
+	grantOption = grantOption || on.grantOption
+	objectT, database, object := on.objectT, on.database, on.object
+
+	// After normalizePerms, we may have empty privileges. If so, skip this grant.
+	if len(privileges) == 0 {
+		return nil, nil
+	}
+
+	if objectT != "" {
+		grant := &ProcedurePrivilegeGrant{
+			Database:     database,
+			ObjectT:      objectT,
+			CallableName: object,
+			Privileges:   privileges,
+			Grant:        grantOption,
+			UserOrRole:   userOrRole,
+			TLSOption:    tlsOption,
+		}
+		log.Printf("[DEBUG] Got procedure parsed grant: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
+		return grant, nil
+	}
+
+	grant := &TablePrivilegeGrant{
+		Database:   database,
+		Table:      object,
+		Privileges: privileges,
+		Grant:      grantOption,
+		UserOrRole: userOrRole,
+		TLSOption:  tlsOption,
+	}
+	log.Printf("[DEBUG] Got table parsed grant: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
+	return grant, nil
+}
+
+// privilegesOnObject is the part of GRANT and REVOKE statements between the keyword and TO or FROM.
+type privilegesOnObject struct {
+	privileges  []string
+	grantOption bool
+	objectT     ObjectT
+	database    string
+	object      string
+}
+
+func (p *grantParser) parsePrivilegesOnObject() (privilegesOnObject, error) {
+	var on privilegesOnObject
+	rawPrivileges, err := p.parsePrivileges()
+	if err != nil {
+		return on, err
+	}
+	// Some servers list GRANT OPTION as a privilege instead of adding WITH GRANT OPTION.
+	for i := 0; i < len(rawPrivileges); i++ {
+		if strings.EqualFold(rawPrivileges[i], "GRANT OPTION") {
+			on.grantOption = true
+			rawPrivileges = append(rawPrivileges[:i], rawPrivileges[i+1:]...)
+			i--
+		}
+	}
+	on.privileges = normalizePerms(rawPrivileges)
+
+	if !p.acceptKeyword("ON") {
+		return on, p.errorf("expected ON")
+	}
+	if t, ok := p.peek(); ok && (t.isKeyword("PROCEDURE") || t.isKeyword("FUNCTION")) {
+		on.objectT = ObjectT(strings.ToUpper(t.value))
+		p.pos++
+	} else {
+		p.acceptKeyword("TABLE")
+	}
+	if on.database, err = p.parseObjectName(); err != nil {
+		return on, err
+	}
+	if !p.acceptPunct(".") {
+		return on, p.errorf("expected database.object")
+	}
+	if on.object, err = p.parseObjectName(); err != nil {
+		return on, err
+	}
+	return on, nil
+}
+
+// parsePartialRevokeFromRow parses REVOKE rows, which SHOW GRANTS returns for partial revokes
+// (partial_revokes=ON). Other rows return nil.
+func parsePartialRevokeFromRow(row string) (*TablePrivilegeGrant, error) {
+	tokens, err := tokenizeGrant(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to tokenize grant statement %s: %w", row, err)
+	}
+	p := &grantParser{stmt: row, tokens: tokens}
+	if !p.acceptKeyword("REVOKE") {
+		return nil, nil
+	}
+
+	on, err := p.parsePrivilegesOnObject()
+	if err != nil {
+		return nil, err
+	}
+	if on.objectT != "" || on.object != "*" {
+		return nil, p.errorf("partial revokes are only expected on databases")
+	}
+	if !p.acceptKeyword("FROM") {
+		return nil, p.errorf("expected FROM")
+	}
+	userOrRole, err := p.parseAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TablePrivilegeGrant{
+		Database:   on.database,
+		Table:      on.object,
+		Privileges: on.privileges,
+		Grant:      on.grantOption,
+		UserOrRole: userOrRole,
+	}, nil
+}
+
+func (p *grantParser) parseRoleGrant() (MySQLGrant, error) {
+	var roles []string
+	for {
+		role, err := p.parseAccount()
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, formatRoleReference(role))
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+
+	if !p.acceptKeyword("TO") {
+		return nil, p.errorf("expected TO")
+	}
+	userOrRole, err := p.parseAccount()
+	if err != nil {
+		return nil, err
+	}
+	tlsOption, grantOption := p.parseTrailingClauses()
+
+	grant := &RoleGrant{
+		Roles:      roles,
+		Grant:      grantOption,
+		UserOrRole: userOrRole,
+		TLSOption:  tlsOption,
+	}
+	log.Printf("[DEBUG] Got: %s, parsed grant is %s: %v", p.stmt, reflect.TypeOf(grant), grant)
+	return grant, nil
+}
@@ -0,0 +1,221 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Rows returned by SHOW GRANTS on the servers the provider supports.
+var kShowGrantsCorpus = []struct {
+	server string
+	row    string
+	grant  MySQLGrant
+	err    bool
+}{
+	{
+		server: "MySQL 5.6",
+		row:    "GRANT SELECT ON `app`.* TO 'jdoe'@'%' REQUIRE SSL",
+		grant: &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "SSL"},
+	},
+	{
+		server: "MySQL 5.7",
+		row:    "GRANT USAGE ON *.* TO 'jdoe'@'%'",
+	},
+	{
+		server: "MySQL 5.7",
+		row:    "GRANT SELECT, INSERT, CREATE TEMPORARY TABLES ON `app`.* TO 'jdoe'@'10.0.0.%' WITH GRANT OPTION",
+		grant: &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"CREATE TEMPORARY TABLES", "INSERT", "SELECT"},
+			Grant: true, UserOrRole: UserOrRole{Name: "jdoe", Host: "10.0.0.%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT APPLICATION_PASSWORD_ADMIN,AUDIT_ADMIN,BACKUP_ADMIN ON *.* TO `admin`@`localhost` WITH GRANT OPTION",
+		grant: &TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"APPLICATION_PASSWORD_ADMIN", "AUDIT_ADMIN", "BACKUP_ADMIN"},
+			Grant: true, UserOrRole: UserOrRole{Name: "admin", Host: "localhost"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT SELECT (`b`, `a`), INSERT (`c`) ON `app`.`t` TO `jdoe`@`%`",
+		grant: &TablePrivilegeGrant{Database: "app", Table: "t", Privileges: []string{"INSERT(`C`)", "SELECT(`A`, `B`)"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT EXECUTE, ALTER ROUTINE ON PROCEDURE `app`.`do_it` TO `jdoe`@`%`",
+		grant: &ProcedurePrivilegeGrant{Database: "app", ObjectT: "PROCEDURE", CallableName: "do_it", Privileges: []string{"ALTER ROUTINE", "EXECUTE"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT `reader`@`%`,`writer`@`10.0.0.%` TO `jdoe`@`%` WITH ADMIN OPTION",
+		grant: &RoleGrant{Roles: []string{"reader", "writer@10.0.0.%"}, Grant: true,
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT SELECT ON `my.db`.`odd``table name` TO `svc@app`@`%`",
+		grant: &TablePrivilegeGrant{Database: "my.db", Table: "odd`table name", Privileges: []string{"SELECT"},
+			UserOrRole: UserOrRole{Name: "svc@app", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT ALL PRIVILEGES ON `app\\_%`.* TO `jdoe`@`%`",
+		grant: &TablePrivilegeGrant{Database: "app\\_%", Table: "*", Privileges: []string{"ALL PRIVILEGES"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "REVOKE INSERT ON `mysql`.* FROM `jdoe`@`%`",
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT PROXY ON ``@`` TO `root`@`localhost` WITH GRANT OPTION",
+	},
+	{
+		server: "MySQL 8.0",
+		row:    "GRANT PROXY ON `app_rw`@`%` TO ``@``",
+	},
+	{
+		server: "MySQL 8.4",
+		row:    "GRANT SELECT, SHOW VIEW ON `app`.* TO `reporting`@`%` WITH GRANT OPTION",
+		grant: &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT", "SHOW VIEW"},
+			Grant: true, UserOrRole: UserOrRole{Name: "reporting", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "MariaDB 10.11",
+		row:    "GRANT USAGE ON *.* TO `jdoe`@`%` IDENTIFIED BY PASSWORD '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'",
+	},
+	{
+		server: "MariaDB 10.11",
+		row:    "GRANT SELECT ON `app`.* TO `jdoe`@`%` IDENTIFIED VIA ed25519 USING 'Zmu0wr' REQUIRE SSL WITH GRANT OPTION",
+		grant: &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT"},
+			Grant: true, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "SSL"},
+	},
+	{
+		server: "MariaDB 10.11",
+		row:    "GRANT `reader` TO `jdoe`@`%`",
+		grant: &RoleGrant{Roles: []string{"reader"},
+			UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "Percona 8.0",
+		row:    "GRANT SELECT, RELOAD, PROCESS, REPLICATION CLIENT ON *.* TO `pmm`@`127.0.0.1`",
+		grant: &TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"PROCESS", "RELOAD", "REPLICATION CLIENT", "SELECT"},
+			UserOrRole: UserOrRole{Name: "pmm", Host: "127.0.0.1"}, TLSOption: "NONE"},
+	},
+	{
+		server: "TiDB 7.5",
+		row:    "GRANT SELECT,INSERT ON `test`.* TO 'u'@'%'",
+		grant: &TablePrivilegeGrant{Database: "test", Table: "*", Privileges: []string{"INSERT", "SELECT"},
+			UserOrRole: UserOrRole{Name: "u", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "TiDB 7.5",
+		row:    "GRANT 'r1'@'%' TO 'u'@'%'",
+		grant: &RoleGrant{Roles: []string{"r1"},
+			UserOrRole: UserOrRole{Name: "u", Host: "%"}, TLSOption: "NONE"},
+	},
+	{
+		server: "broken",
+		row:    "GRANT SELECT ON `app TO 'u'@'%'",
+		err:    true,
+	},
+	{
+		server: "broken",
+		row:    "SELECT 1",
+		err:    true,
+	},
+}
+
+func TestParseGrantFromRow(t *testing.T) {
+	for _, tc := range kShowGrantsCorpus {
+		t.Run(tc.server+": "+tc.row, func(t *testing.T) {
+			grant, err := parseGrantFromRow(tc.row)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %#v", grant)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.grant == nil {
+				if grant != nil {
+					t.Fatalf("expected the row to be skipped, got %#v", grant)
+				}
+				return
+			}
+			if !reflect.DeepEqual(grant, tc.grant) {
+				t.Errorf("parsed %#v, expected %#v", grant, tc.grant)
+			}
+		})
+	}
+}
+
+func FuzzParseGrantFromRow(f *testing.F) {
+	for _, tc := range kShowGrantsCorpus {
+		f.Add(tc.row)
+	}
+	f.Fuzz(func(t *testing.T, row string) {
+		if revoke, err := parsePartialRevokeFromRow(row); err == nil && revoke != nil && revoke.Table != "*" {
+			t.Errorf("partial revoke on a table parsed from %q", row)
+		}
+
+		grant, err := parseGrantFromRow(row)
+		if err != nil || grant == nil {
+			return
+		}
+		if grant.GetUserOrRole().Name == "" && grant.GetId() == "" {
+			t.Errorf("grant without grantee parsed from %q", row)
+		}
+		again, _ := parseGrantFromRow(row)
+		if !reflect.DeepEqual(grant, again) {
+			t.Errorf("parsing %q is not deterministic", row)
+		}
+	})
+}
+
+func TestParsePartialRevokeFromRow(t *testing.T) {
+	revoke, err := parsePartialRevokeFromRow("REVOKE SELECT, INSERT ON `pay.roll`.* FROM `jdoe`@`%`")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &TablePrivilegeGrant{Database: "pay.roll", Table: "*", Privileges: []string{"INSERT", "SELECT"},
+		UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	if !reflect.DeepEqual(revoke, expected) {
+		t.Errorf("parsed %#v, expected %#v", revoke, expected)
+	}
+
+	if revoke, err := parsePartialRevokeFromRow("GRANT SELECT ON *.* TO `jdoe`@`%`"); revoke != nil || err != nil {
+		t.Errorf("expected GRANT rows to be ignored, got %#v, %v", revoke, err)
+	}
+	if _, err := parsePartialRevokeFromRow("REVOKE SELECT ON `app`.`t` FROM `jdoe`@`%`"); err == nil {
+		t.Errorf("expected an error for a table level revoke")
+	}
+
+	global := &TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	other := &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	attachPartialRevokes([]MySQLGrant{other, global}, []*TablePrivilegeGrant{revoke, revoke})
+	if !reflect.DeepEqual(global.RevokedDatabases, []string{"pay.roll"}) || other.RevokedDatabases != nil {
+		t.Errorf("unexpected revoked databases %v and %v", global.RevokedDatabases, other.RevokedDatabases)
+	}
+	if !reflect.DeepEqual(global.RevokedPrivileges, map[string][]string{"pay.roll": {"INSERT", "SELECT"}}) {
+		t.Errorf("unexpected revoked privileges %v", global.RevokedPrivileges)
+	}
+	if databases := global.fullyRevokedDatabases(); !reflect.DeepEqual(databases, []string{"pay.roll"}) {
+		t.Errorf("unexpected fully revoked databases %v", databases)
+	}
+
+	// INSERT was granted on the database again, so only SELECT is still revoked there.
+	drifted := &TablePrivilegeGrant{Database: "*", Table: "*", Privileges: []string{"INSERT", "SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	partial := &TablePrivilegeGrant{Database: "pay.roll", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	attachPartialRevokes([]MySQLGrant{drifted}, []*TablePrivilegeGrant{partial})
+	if databases := drifted.fullyRevokedDatabases(); len(databases) != 0 {
+		t.Errorf("expected the partially revoked database to be drift, got %v", databases)
+	}
+	if stmt := global.SQLRevokeOnDatabaseStatement("pay`roll"); stmt != "REVOKE SELECT ON `pay``roll`.* FROM 'jdoe'@'%'" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+}
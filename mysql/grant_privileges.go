@@ -0,0 +1,203 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Privileges are validated during plan against SHOW PRIVILEGES, which also lists the dynamic privileges
+// registered by the server and its components. Each privilege has to exist and be grantable on the
+// level of the grant, so typos and e.g. RELOAD on a table fail before anything is applied.
+
+type privilegeLevel string
+
+const (
+	privilegeLevelGlobal   privilegeLevel = "global"
+	privilegeLevelDatabase privilegeLevel = "database"
+	privilegeLevelTable    privilegeLevel = "table"
+	privilegeLevelRoutine  privilegeLevel = "routine"
+)
+
+// serverPrivileges maps the uppercased privilege names to the levels they can be granted on.
+type serverPrivileges map[string][]privilegeLevel
+
+var serverPrivilegesCache = struct {
+	mtx     sync.Mutex
+	entries map[*sql.DB]serverPrivileges
+}{entries: map[*sql.DB]serverPrivileges{}}
+
+// privilegeLevelsFromContext parses the Context column of SHOW PRIVILEGES, e.g. "Databases,Tables".
+// Privileges of contexts like "Server Admin" or "File access" can only be granted globally.
+func privilegeLevelsFromContext(privilegeContext string) []privilegeLevel {
+	levels := []privilegeLevel{privilegeLevelGlobal}
+	for _, part := range strings.Split(privilegeContext, ",") {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "databases":
+			levels = append(levels, privilegeLevelDatabase)
+		case "tables", "indexes":
+			levels = append(levels, privilegeLevelDatabase, privilegeLevelTable)
+		case "functions", "procedures":
+			levels = append(levels, privilegeLevelDatabase, privilegeLevelRoutine)
+		}
+	}
+	slices.Sort(levels)
+	return slices.Compact(levels)
+}
+
+func getServerPrivileges(ctx context.Context, db *sql.DB) (serverPrivileges, error) {
+	serverPrivilegesCache.mtx.Lock()
+	defer serverPrivilegesCache.mtx.Unlock()
+	if privileges, ok := serverPrivilegesCache.entries[db]; ok {
+		return privileges, nil
+	}
+
+	log.Println("[DEBUG] SQL to list privileges: SHOW PRIVILEGES")
+	rows, err := db.QueryContext(ctx, "SHOW PRIVILEGES")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	privileges := serverPrivileges{}
+	for rows.Next() {
+		var name, privilegeContext, comment sql.NullString
+		if err := rows.Scan(&name, &privilegeContext, &comment); err != nil {
+			return nil, err
+		}
+		privileges[strings.ToUpper(name.String)] = privilegeLevelsFromContext(privilegeContext.String)
+	}
+	// SHOW PRIVILEGES reports EVENT as "Server Admin", but it's granted on databases.
+	if levels, ok := privileges["EVENT"]; ok && !slices.Contains(levels, privilegeLevelDatabase) {
+		privileges["EVENT"] = privilegeLevelsFromContext("Databases")
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	serverPrivilegesCache.entries[db] = privileges
+	return privileges, nil
+}
+
+// grantLevel returns the level of a grant on database and table, as written in mysql_grant.
+func grantLevel(database, table string) privilegeLevel {
+	switch {
+	case kReProcedureWithDatabase.MatchString(database) || kReProcedureWithoutDatabase.MatchString(database):
+		return privilegeLevelRoutine
+	case database == "*":
+		return privilegeLevelGlobal
+	case table == "*" || table == "":
+		return privilegeLevelDatabase
+	}
+	return privilegeLevelTable
+}
+
+// validate returns an error for every privilege that is unknown or can't be granted on level.
+func (p serverPrivileges) validate(privileges []string, level privilegeLevel) []error {
+	errs := []error{}
+	for _, privilege := range normalizePerms(privileges) {
+		name := privilege
+		isColumnPrivilege := false
+		if m := kReColumnPrivilege.FindStringSubmatch(privilege); m != nil {
+			name = strings.TrimSpace(m[1])
+			isColumnPrivilege = true
+		}
+		if name == "ALL PRIVILEGES" {
+			continue
+		}
+
+		levels, ok := p[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%q is not a privilege known to the server (see SHOW PRIVILEGES)", name))
+		case isColumnPrivilege && (level != privilegeLevelTable || !slices.Contains(levels, privilegeLevelTable)):
+			errs = append(errs, fmt.Errorf("%q: column privileges can only be granted on a table", privilege))
+		case !slices.Contains(levels, level):
+			allowed := make([]string, len(levels))
+			for i, allowedLevel := range levels {
+				allowed[i] = string(allowedLevel)
+			}
+			errs = append(errs, fmt.Errorf("%s can't be granted on %s level, only on: %s", name, level, strings.Join(allowed, ", ")))
+		}
+	}
+	return errs
+}
+
+// serverPrivilegesForDiff returns the privileges of the server, or nil if they can't be listed,
+// in which case validation is left to the server.
+func serverPrivilegesForDiff(ctx context.Context, meta interface{}) (serverPrivileges, error) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := getServerPrivileges(ctx, db)
+	if err != nil {
+		log.Printf("[WARN] Skipping validation of privileges, SHOW PRIVILEGES failed: %v", err)
+		return nil, nil
+	}
+	return privileges, nil
+}
+
+func prefixErrors(prefix string, errs []error) []error {
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s: %w", prefix, err)
+	}
+	return errs
+}
+
+// validateGrantPrivileges checks the privileges of mysql_grant during plan.
+func validateGrantPrivileges(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChanges("privileges", "database", "table") ||
+		!d.NewValueKnown("privileges") || !d.NewValueKnown("database") || !d.NewValueKnown("table") {
+		return nil
+	}
+	privileges := setToArray(d.Get("privileges"))
+	if len(privileges) == 0 {
+		return nil
+	}
+
+	server, err := serverPrivilegesForDiff(ctx, meta)
+	if err != nil || server == nil {
+		return err
+	}
+
+	level := grantLevel(d.Get("database").(string), d.Get("table").(string))
+	if d.Get("table_include").(*schema.Set).Len() > 0 || d.Get("table_exclude").(*schema.Set).Len() > 0 {
+		level = privilegeLevelTable
+	}
+	return errors.Join(prefixErrors("privileges", server.validate(privileges, level))...)
+}
+
+// validateUserGrantsPrivileges checks the privileges of every grant block of mysql_user_grants and
+// mysql_account during plan.
+func validateUserGrantsPrivileges(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("grant") || !d.NewValueKnown("grant") {
+		return nil
+	}
+	blocks := d.Get("grant").(*schema.Set).List()
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	server, err := serverPrivilegesForDiff(ctx, meta)
+	if err != nil || server == nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, elem := range blocks {
+		m := elem.(map[string]interface{})
+		database := m["database"].(string)
+		table := m["table"].(string)
+		prefix := fmt.Sprintf("grant (database = %q, table = %q): privileges", database, table)
+		errs = append(errs, prefixErrors(prefix, server.validate(setToArray(m["privileges"]), grantLevel(database, table)))...)
+	}
+	return errors.Join(errs...)
+}
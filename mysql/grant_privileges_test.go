@@ -0,0 +1,62 @@
+package mysql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrivilegeLevelsFromContext(t *testing.T) {
+	for context, expected := range map[string][]privilegeLevel{
+		"Server Admin":                          {privilegeLevelGlobal},
+		"File access on server":                 {privilegeLevelGlobal},
+		"Databases":                             {privilegeLevelDatabase, privilegeLevelGlobal},
+		"Tables":                                {privilegeLevelDatabase, privilegeLevelGlobal, privilegeLevelTable},
+		"Functions,Procedures":                  {privilegeLevelDatabase, privilegeLevelGlobal, privilegeLevelRoutine},
+		"Databases,Tables,Indexes":              {privilegeLevelDatabase, privilegeLevelGlobal, privilegeLevelTable},
+		"Databases,Tables,Functions,Procedures": {privilegeLevelDatabase, privilegeLevelGlobal, privilegeLevelRoutine, privilegeLevelTable},
+	} {
+		if levels := privilegeLevelsFromContext(context); !reflect.DeepEqual(levels, expected) {
+			t.Errorf("privilegeLevelsFromContext(%q) = %v, expected %v", context, levels, expected)
+		}
+	}
+}
+
+func TestValidatePrivileges(t *testing.T) {
+	server := serverPrivileges{
+		"SELECT":           privilegeLevelsFromContext("Tables"),
+		"RELOAD":           privilegeLevelsFromContext("Server Admin"),
+		"EXECUTE":          privilegeLevelsFromContext("Functions,Procedures"),
+		"CONNECTION_ADMIN": privilegeLevelsFromContext("Server Admin"),
+	}
+
+	for _, tc := range []struct {
+		privileges []string
+		database   string
+		table      string
+		errors     []string
+	}{
+		{[]string{"select", "RELOAD", "CONNECTION_ADMIN", "ALL"}, "*", "*", nil},
+		{[]string{"SELECT", "SELECT (a, b)"}, "app", "t", nil},
+		{[]string{"SELCT"}, "app", "*", []string{`"SELCT" is not a privilege known to the server`}},
+		{[]string{"RELOAD"}, "app", "t", []string{"RELOAD can't be granted on table level, only on: global"}},
+		{[]string{"EXECUTE"}, "PROCEDURE app.do_it", "*", nil},
+		{[]string{"SELECT"}, "procedure app.do_it", "*", []string{"SELECT can't be granted on routine level, only on: database, global, table"}},
+		{[]string{"SELECT(a)"}, "app", "*", []string{`"SELECT(` + "`A`" + `)": column privileges can only be granted on a table`}},
+	} {
+		errs := server.validate(tc.privileges, grantLevel(tc.database, tc.table))
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		if len(messages) != len(tc.errors) {
+			t.Errorf("validating %v on %s.%s: got %q, expected %q", tc.privileges, tc.database, tc.table, messages, tc.errors)
+			continue
+		}
+		for i := range messages {
+			if !strings.Contains(messages[i], tc.errors[i]) {
+				t.Errorf("validating %v on %s.%s: got %q, expected %q", tc.privileges, tc.database, tc.table, messages[i], tc.errors[i])
+			}
+		}
+	}
+}
@@ -295,20 +295,30 @@ func Provider() *schema.Provider {
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"mysql_databases": dataSourceDatabases(),
+			"mysql_discovery": dataSourceDiscovery(),
 			"mysql_tables":    dataSourceTables(),
+			"mysql_users":     dataSourceUsers(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"mysql_database":        resourceDatabase(),
-			"mysql_global_variable": resourceGlobalVariable(),
-			"mysql_grant":           resourceGrant(),
-			"mysql_role":            resourceRole(),
-			"mysql_sql":             resourceSql(),
-			"mysql_user_password":   resourceUserPassword(),
-			"mysql_user":            resourceUser(),
-			"mysql_ti_config":       resourceTiConfigVariable(),
-			"mysql_rds_config":      resourceRDSConfig(),
-			"mysql_default_roles":   resourceDefaultRoles(),
+			"mysql_account":                resourceAccount(),
+			"mysql_database":               resourceDatabase(),
+			"mysql_global_variable":        resourceGlobalVariable(),
+			"mysql_grant":                  resourceGrant(),
+			"mysql_proxy_grant":            resourceProxyGrant(),
+			"mysql_role":                   resourceRole(),
+			"mysql_sql":                    resourceSql(),
+			"mysql_user_password":          resourceUserPassword(),
+			"mysql_user_password_rotation": resourceUserPasswordRotation(),
+			"mysql_user":                   resourceUser(),
+			"mysql_user_grants":            resourceUserGrants(),
+			"mysql_ti_config":              resourceTiConfigVariable(),
+			"mysql_rds_config":             resourceRDSConfig(),
+			"mysql_default_roles":          resourceDefaultRoles(),
+			"mysql_discard_old_password":   resourceDiscardOldPassword(),
+			"mysql_expire_password":        resourceExpirePassword(),
+			"mysql_flush":                  resourceFlush(),
+			"mysql_kill_user_sessions":     resourceKillUserSessions(),
 		},
 
 		ConfigureContextFunc: providerConfigure,
@@ -790,6 +800,15 @@ func serverTiDB(db *sql.DB) (bool, string, string, error) {
 	return false, "", "", nil
 }
 
+func serverMariaDB(db *sql.DB) (bool, error) {
+	currentVersionString, err := serverVersionString(db)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.Contains(currentVersionString, "MariaDB"), nil
+}
+
 func serverRds(db *sql.DB) (bool, error) {
 	var metadataVersionString string
 	err := db.QueryRow("SELECT @@GLOBAL.datadir").Scan(&metadataVersionString)
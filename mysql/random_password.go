@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// MySQL 8.0.18+ can generate passwords with IDENTIFIED BY RANDOM PASSWORD. The statement returns
+// the password in its "generated password" column, the only time it can be seen. Its length is
+// taken from the session variable generated_random_password_length.
+
+const (
+	minRandomPasswordLength = 5
+	maxRandomPasswordLength = 255
+)
+
+func checkRandomPasswordSupport(ctx context.Context, meta interface{}) error {
+	ver, _ := version.NewVersion("8.0.18")
+	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+		return fmt.Errorf("MySQL version must be at least 8.0.18")
+	}
+	return nil
+}
+
+// randomPasswordStatement returns the ALTER USER statement generating a new password.
+func randomPasswordStatement(retainPassword bool) string {
+	if retainPassword {
+		return "ALTER USER ?@? IDENTIFIED BY RANDOM PASSWORD RETAIN CURRENT PASSWORD"
+	}
+	return "ALTER USER ?@? IDENTIFIED BY RANDOM PASSWORD"
+}
+
+// execRandomPassword runs a statement with BY RANDOM PASSWORD and returns the generated password.
+// A length of 0 keeps the server default.
+func execRandomPassword(ctx context.Context, db *sql.DB, length int, stmtSQL string, args ...interface{}) (string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	if length > 0 {
+		lengthSQL := fmt.Sprintf("SET SESSION generated_random_password_length = %d", length)
+		log.Println("[DEBUG] Executing statement:", lengthSQL)
+		if _, err := conn.ExecContext(ctx, lengthSQL); err != nil {
+			return "", fmt.Errorf("failed setting random password length: %w", err)
+		}
+		defer conn.ExecContext(ctx, "SET SESSION generated_random_password_length = DEFAULT")
+	}
+
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	rows, err := conn.QueryContext(ctx, stmtSQL, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", fmt.Errorf("failed reading generated password: %w", err)
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("no password was generated")
+	}
+
+	values := make([]sql.NullString, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return "", fmt.Errorf("failed reading generated password: %w", err)
+	}
+	for i, column := range columns {
+		if column == "generated password" {
+			return values[i].String, nil
+		}
+	}
+	return "", fmt.Errorf("no generated password in columns %v", columns)
+}
+
+// diffUserRandomPassword shows the generated password of mysql_user as unknown when a new one
+// is going to be generated, i.e. when random_password is turned on, its length, the keepers or
+// auth_plugin change.
+func diffUserRandomPassword(d *schema.ResourceDiff) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if !d.Get("random_password").(bool) {
+		if d.HasChange("random_password") {
+			return d.SetNew("generated_password", "")
+		}
+		return nil
+	}
+	if d.HasChanges("random_password", "random_password_length", "keepers", "auth_plugin") {
+		return d.SetNewComputed("generated_password")
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceAccount manages one user at several hosts as a single account: every host gets the same
+// authentication and the same grants. Like mysql_user_grants, grants that are not listed are revoked.
+func resourceAccount() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateAccount,
+		UpdateContext: UpdateAccount,
+		ReadContext:   ReadAccount,
+		DeleteContext: DeleteAccount,
+		CustomizeDiff: customizeAccountDiff,
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"hosts": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateAccountHost,
+				},
+				Set: schema.HashString,
+			},
+
+			"plaintext_password": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+				StateFunc: hashSum,
+			},
+
+			"auth_plugin": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: NewEmptyStringSuppressFunc,
+			},
+
+			"auth_string_hashed": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"plaintext_password"},
+				RequiredWith:  []string{"auth_plugin"},
+			},
+
+			"grant": userGrantsGrantSchema(),
+
+			"host_patterns": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// accountCredentialKeys are the attributes that set the password or the authentication string.
+var accountCredentialKeys = []string{"plaintext_password", "auth_string_hashed"}
+
+func customizeAccountDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateUserGrantsPrivileges(ctx, d, meta); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("auth_plugin") {
+		newPlugin := d.Get("auth_plugin").(string)
+		if kPasswordAuthPlugins[newPlugin] && !configuresCredential(d.GetRawConfig(), accountCredentialKeys) {
+			// ALTER USER ... IDENTIFIED WITH without BY or AS clears the password.
+			return fmt.Errorf("changing auth_plugin to %s needs plaintext_password or auth_string_hashed to be set", newPlugin)
+		}
+	}
+	return nil
+}
+
+// validateAccountHost accepts MySQL host patterns and CIDRs. Anything else with a slash has to be
+// an IPv4 address with a netmask.
+func validateAccountHost(v interface{}, k string) ([]string, []error) {
+	host := v.(string)
+	address, mask, ok := strings.Cut(host, "/")
+	if !ok {
+		return nil, nil
+	}
+	if _, _, err := net.ParseCIDR(host); err == nil {
+		return nil, nil
+	}
+	if net.ParseIP(address).To4() == nil || net.ParseIP(mask).To4() == nil {
+		return nil, []error{fmt.Errorf("%s: %q must be a host pattern, a CIDR or an IPv4 address with a netmask", k, host)}
+	}
+	return nil, nil
+}
+
+// accountHostPattern returns the host of the account for a host of mysql_account. IPv4 CIDRs are
+// kept where MySQL supports them (8.0.23+), and converted to a netmask elsewhere.
+func accountHostPattern(host string, cidrSupported bool) (string, error) {
+	_, network, err := net.ParseCIDR(host)
+	if err != nil {
+		return host, nil
+	}
+	if network.IP.To4() == nil {
+		return "", fmt.Errorf("host %s: MySQL only supports IPv4 networks", host)
+	}
+	if cidrSupported {
+		return network.String(), nil
+	}
+	return fmt.Sprintf("%s/%s", network.IP, net.IP(network.Mask)), nil
+}
+
+func accountCIDRSupported(ctx context.Context, db *sql.DB, meta interface{}) (bool, error) {
+	if isMariaDB, err := serverMariaDB(db); err != nil || isMariaDB {
+		return false, err
+	}
+	if isTiDB, _, _, err := serverTiDB(db); err != nil || isTiDB {
+		return false, err
+	}
+	ver, _ := version.NewVersion("8.0.23")
+	return getVersionFromMeta(ctx, meta).GreaterThanOrEqual(ver), nil
+}
+
+// accountHostPatterns returns the hosts of the account by the hosts given in hosts.
+func accountHostPatterns(ctx context.Context, db *sql.DB, meta interface{}, hosts []string) (map[string]string, error) {
+	cidrSupported, err := accountCIDRSupported(ctx, db, meta)
+	if err != nil {
+		return nil, err
+	}
+	patterns := map[string]string{}
+	for _, host := range hosts {
+		pattern, err := accountHostPattern(host, cidrSupported)
+		if err != nil {
+			return nil, err
+		}
+		patterns[host] = pattern
+	}
+	return patterns, nil
+}
+
+// storedAccountHostPatterns returns the hosts of the account as they were created. They are kept in
+// host_patterns, as a CIDR is created in a different form depending on the server version. Hosts that
+// aren't in it yet get the pattern of the current server.
+func storedAccountHostPatterns(ctx context.Context, db *sql.DB, meta interface{}, d *schema.ResourceData, hosts []string) (map[string]string, error) {
+	stored := d.Get("host_patterns").(map[string]interface{})
+	missing := []string{}
+	for _, host := range hosts {
+		if _, ok := stored[host]; !ok {
+			missing = append(missing, host)
+		}
+	}
+	patterns := map[string]string{}
+	if len(missing) > 0 {
+		var err error
+		if patterns, err = accountHostPatterns(ctx, db, meta, missing); err != nil {
+			return nil, err
+		}
+	}
+	for _, host := range hosts {
+		if pattern, ok := stored[host]; ok {
+			patterns[host] = pattern.(string)
+		}
+	}
+	return patterns, nil
+}
+
+// accountIdentifiedSQL returns the IDENTIFIED clause of CREATE or ALTER USER, if any.
+func accountIdentifiedSQL(d *schema.ResourceData) (string, []interface{}) {
+	stmtSQL := ""
+	if plugin := d.Get("auth_plugin").(string); plugin != "" {
+		stmtSQL = " IDENTIFIED WITH " + plugin
+	}
+	if hashed := d.Get("auth_string_hashed").(string); hashed != "" {
+		return stmtSQL + " AS ?", []interface{}{hashed}
+	}
+	if password := configuredString(d, "plaintext_password"); password != "" {
+		if stmtSQL == "" {
+			stmtSQL = " IDENTIFIED"
+		}
+		return stmtSQL + " BY ?", []interface{}{password}
+	}
+	return stmtSQL, nil
+}
+
+func createAccountHost(ctx context.Context, db *sql.DB, d *schema.ResourceData, userOrRole UserOrRole) error {
+	identifiedSQL, identifiedArgs := accountIdentifiedSQL(d)
+	stmtSQL := "CREATE USER ?@?" + identifiedSQL
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	args := append([]interface{}{userOrRole.Name, userOrRole.Host}, identifiedArgs...)
+	if _, err := db.ExecContext(ctx, stmtSQL, args...); err != nil {
+		return fmt.Errorf("failed creating user %s: %w", userOrRole.SQLString(), err)
+	}
+	return applyAccountGrants(ctx, db, d, userOrRole)
+}
+
+func dropAccountHost(ctx context.Context, db *sql.DB, userOrRole UserOrRole) error {
+	stmtSQL := "DROP USER ?@?"
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL, userOrRole.Name, userOrRole.Host); err != nil && mysqlErrorNumber(err) != unknownUserErrCode {
+		return fmt.Errorf("failed dropping user %s: %w", userOrRole.SQLString(), err)
+	}
+	return nil
+}
+
+// applyAccountGrants turns the grants of one host of the account into the configured ones.
+func applyAccountGrants(ctx context.Context, db *sql.DB, d *schema.ResourceData, userOrRole UserOrRole) error {
+	desired, err := expandUserGrants(d, userOrRole)
+	if err != nil {
+		return err
+	}
+
+	grantCreateMutex.Lock(userOrRole.IDString())
+	defer grantCreateMutex.Unlock(userOrRole.IDString())
+	defer invalidateUserGrants(db, userOrRole)
+
+	current, currentRoles, err := currentUserGrants(ctx, db, userOrRole)
+	if err != nil {
+		return fmt.Errorf("failed reading grants of %s: %w", userOrRole.SQLString(), err)
+	}
+
+	// Roles aren't managed here, so they are kept.
+	statements, err := userGrantsStatements(userOrRole, current, currentRoles, desired, currentRoles)
+	if err != nil {
+		return err
+	}
+	for _, stmtSQL := range statements {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("error running SQL (%s): %w", stmtSQL, err)
+		}
+	}
+	return nil
+}
+
+func sortedAccountHosts(patterns map[string]string) []string {
+	hosts := make([]string, 0, len(patterns))
+	for host := range patterns {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func CreateAccount(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	patterns, err := accountHostPatterns(ctx, db, meta, setToArray(d.Get("hosts")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	created := []string{}
+	createdPatterns := map[string]interface{}{}
+	for _, host := range sortedAccountHosts(patterns) {
+		if err := createAccountHost(ctx, db, d, UserOrRole{Name: user, Host: patterns[host]}); err != nil {
+			if len(created) > 0 {
+				// Keep the hosts that were created, so they are dropped with the tainted resource.
+				d.SetId(escapeIdPart(user))
+				d.Set("hosts", created)
+				d.Set("host_patterns", createdPatterns)
+			}
+			return diag.FromErr(err)
+		}
+		created = append(created, host)
+		createdPatterns[host] = patterns[host]
+	}
+
+	d.SetId(escapeIdPart(user))
+	return ReadAccount(ctx, d, meta)
+}
+
+func UpdateAccount(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	oldHosts, newHosts := d.GetChange("hosts")
+	oldPatterns, err := storedAccountHostPatterns(ctx, db, meta, d, setToArray(oldHosts))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	newPatterns, err := storedAccountHostPatterns(ctx, db, meta, d, setToArray(newHosts))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, host := range sortedAccountHosts(oldPatterns) {
+		if _, ok := newPatterns[host]; !ok {
+			if err := dropAccountHost(ctx, db, UserOrRole{Name: user, Host: oldPatterns[host]}); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	authChanged := d.HasChanges("plaintext_password", "auth_plugin", "auth_string_hashed")
+	for _, host := range sortedAccountHosts(newPatterns) {
+		userOrRole := UserOrRole{Name: user, Host: newPatterns[host]}
+		if _, ok := oldPatterns[host]; !ok {
+			if err := createAccountHost(ctx, db, d, userOrRole); err != nil {
+				return diag.FromErr(err)
+			}
+			continue
+		}
+
+		if identifiedSQL, args := accountIdentifiedSQL(d); authChanged && identifiedSQL != "" {
+			stmtSQL := "ALTER USER ?@?" + identifiedSQL
+			log.Println("[DEBUG] Executing statement:", stmtSQL)
+			args = append([]interface{}{userOrRole.Name, userOrRole.Host}, args...)
+			if _, err := db.ExecContext(ctx, stmtSQL, args...); err != nil {
+				return diag.Errorf("failed changing authentication of %s: %v", userOrRole.SQLString(), err)
+			}
+		}
+		if d.HasChange("grant") {
+			if err := applyAccountGrants(ctx, db, d, userOrRole); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return ReadAccount(ctx, d, meta)
+}
+
+// ReadAccount reads every host of the account. Hosts that are gone are removed from the state, and
+// a host whose plugin or grants differ from the others sets them in the state, so that the next
+// apply brings all hosts back in line. Each drifted host is reported as a warning.
+func ReadAccount(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user := d.Get("user").(string)
+	patterns, err := storedAccountHostPatterns(ctx, db, meta, d, setToArray(d.Get("hosts")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	drift := func(host, format string, args ...interface{}) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Host %s of account %s changed outside of Terraform", host, user),
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	stateGrants := d.Get("grant").(*schema.Set)
+	statePlugin := d.Get("auth_plugin").(string)
+	var grants []interface{}
+	plugin := statePlugin
+	hosts := []string{}
+	for _, host := range sortedAccountHosts(patterns) {
+		userOrRole := UserOrRole{Name: user, Host: patterns[host]}
+		var hostPlugin string
+		err := db.QueryRowContext(ctx, "SELECT plugin FROM mysql.user WHERE user = ? AND host = ?", user, userOrRole.Host).Scan(&hostPlugin)
+		if err == sql.ErrNoRows {
+			drift(host, "The user %s doesn't exist.", userOrRole.SQLString())
+			continue
+		}
+		if err != nil {
+			return diag.Errorf("failed reading user %s: %v", userOrRole.SQLString(), err)
+		}
+		hosts = append(hosts, host)
+
+		if statePlugin != "" && hostPlugin != statePlugin {
+			drift(host, "The user %s uses the plugin %s.", userOrRole.SQLString(), hostPlugin)
+			plugin = hostPlugin
+		}
+
+		current, _, err := currentUserGrants(ctx, db, userOrRole)
+		if err != nil {
+			return diag.Errorf("failed reading grants of %s: %v", userOrRole.SQLString(), err)
+		}
+		hostGrants := flattenUserGrants(current, stateGrants.List(), userOrRole)
+		if !stateGrants.Equal(schema.NewSet(stateGrants.F, hostGrants)) {
+			drift(host, "The grants of %s differ from the configured ones.", userOrRole.SQLString())
+			// The grants of the first drifted host are enough to bring all hosts in line.
+			if grants == nil {
+				grants = hostGrants
+			}
+		}
+	}
+	if grants == nil {
+		grants = stateGrants.List()
+	}
+
+	if len(hosts) == 0 {
+		log.Printf("[WARN] Account %s not found at any host, removing from state", user)
+		d.SetId("")
+		return nil
+	}
+
+	hostPatterns := map[string]interface{}{}
+	for _, host := range hosts {
+		hostPatterns[host] = patterns[host]
+	}
+	d.Set("hosts", hosts)
+	d.Set("host_patterns", hostPatterns)
+	d.Set("auth_plugin", plugin)
+	d.Set("grant", grants)
+	return diags
+}
+
+func DeleteAccount(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Dropping the users also drops them from the grants of accounts they were granted to as roles.
+	defer invalidateAllUserGrants(db)
+
+	user := d.Get("user").(string)
+	patterns, err := storedAccountHostPatterns(ctx, db, meta, d, setToArray(d.Get("hosts")))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, host := range sortedAccountHosts(patterns) {
+		if err := dropAccountHost(ctx, db, UserOrRole{Name: user, Host: patterns[host]}); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
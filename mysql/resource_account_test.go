@@ -0,0 +1,186 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccAccount_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccAccountCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAccountConfig(`"10.1.0.0/16", "10.2.%", "localhost"`, `"SELECT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_account.test", "hosts.#", "3"),
+					resource.TestCheckResourceAttr("mysql_account.test", "host_patterns.10.2.%", "10.2.%"),
+					resource.TestCheckResourceAttrSet("mysql_account.test", "host_patterns.10.1.0.0/16"),
+					testAccAccountPrivilege("localhost", "SELECT"),
+					testAccAccountPrivilege("10.2.%", "SELECT"),
+				),
+			},
+			{
+				// A privilege revoked at one host shows up as drift.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("REVOKE SELECT ON `tf-test-account`.* FROM 'jdoe-account'@'10.2.%'"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccAccountConfig(`"10.1.0.0/16", "10.2.%", "localhost"`, `"SELECT"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Changing the plugin without a password would clear it.
+				Config: `
+resource "mysql_account" "test" {
+  user        = "jdoe-account"
+  hosts       = ["10.1.0.0/16", "10.2.%", "localhost"]
+  auth_plugin = "mysql_native_password"
+}
+`,
+				ExpectError: regexp.MustCompile("needs plaintext_password or auth_string_hashed"),
+			},
+			{
+				Config: testAccAccountConfig(`"10.2.%", "localhost"`, `"SELECT", "INSERT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_account.test", "hosts.#", "2"),
+					testAccAccountPrivilege("localhost", "INSERT"),
+					testAccAccountPrivilege("10.2.%", "SELECT"),
+					testAccAccountPrivilege("10.2.%", "INSERT"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAccountConfig(hosts, privileges string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "tf-test-account"
+}
+
+resource "mysql_account" "test" {
+  user               = "jdoe-account"
+  hosts              = [%s]
+  plaintext_password = "password"
+
+  grant {
+    database   = mysql_database.test.name
+    privileges = [%s]
+  }
+}
+`, hosts, privileges)
+}
+
+func testAccAccountPrivilege(host, privilege string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+		grants, _, err := currentUserGrants(ctx, db, UserOrRole{Name: "jdoe-account", Host: host})
+		if err != nil {
+			return err
+		}
+		for _, grant := range grants {
+			for _, p := range grantPrivileges(grant) {
+				if p == privilege {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%s not granted to jdoe-account@%s", privilege, host)
+	}
+}
+
+func testAccAccountCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM mysql.user WHERE user = 'jdoe-account'").Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("account still exists after destroy at %d hosts", count)
+	}
+	return nil
+}
+
+func TestAccountHostPattern(t *testing.T) {
+	tests := []struct {
+		host          string
+		cidrSupported bool
+		expected      string
+	}{
+		{"localhost", true, "localhost"},
+		{"10.1.%", false, "10.1.%"},
+		{"10.1.0.0/16", true, "10.1.0.0/16"},
+		{"10.1.0.0/16", false, "10.1.0.0/255.255.0.0"},
+		{"10.1.2.3/20", false, "10.1.0.0/255.255.240.0"},
+		{"10.1.0.0/255.255.0.0", true, "10.1.0.0/255.255.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			pattern, err := accountHostPattern(tt.host, tt.cidrSupported)
+			if err != nil || pattern != tt.expected {
+				t.Errorf("accountHostPattern returned %q, %v, expected %q", pattern, err, tt.expected)
+			}
+		})
+	}
+	if _, err := accountHostPattern("fd00::/64", true); err == nil {
+		t.Errorf("expected an error for an IPv6 network")
+	}
+
+	for _, host := range []string{"%", "app.example.com", "10.1.0.0/16", "10.1.0.0/255.255.0.0"} {
+		if _, errs := validateAccountHost(host, "hosts"); len(errs) != 0 {
+			t.Errorf("validateAccountHost rejected %q: %v", host, errs)
+		}
+	}
+	for _, host := range []string{"10.1.0.0/x", "app/16"} {
+		if _, errs := validateAccountHost(host, "hosts"); len(errs) == 0 {
+			t.Errorf("validateAccountHost accepted %q", host)
+		}
+	}
+}
+
+func TestStoredAccountHostPatterns(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceAccount().Schema, map[string]interface{}{
+		"user":  "jdoe",
+		"hosts": []interface{}{"10.1.0.0/16", "localhost"},
+	})
+	// The account was created before the server supported CIDRs.
+	d.Set("host_patterns", map[string]interface{}{"10.1.0.0/16": "10.1.0.0/255.255.0.0", "localhost": "localhost"})
+
+	patterns, err := storedAccountHostPatterns(context.Background(), nil, nil, d, []string{"10.1.0.0/16", "localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"10.1.0.0/16": "10.1.0.0/255.255.0.0", "localhost": "localhost"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("storedAccountHostPatterns returned %v, expected %v", patterns, expected)
+	}
+}
@@ -205,10 +205,18 @@ func extractIdentAfter(sql string, keyword string) string {
 }
 
 func ImportDatabase(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name := d.Id()
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("wrong ID format %q (expected DATABASE_NAME)", name)
+	}
+
 	err := ReadDatabase(ctx, d, meta)
 	if err != nil {
 		return nil, fmt.Errorf("error while importing: %v", err)
 	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("database %s does not exist", name)
+	}
 
 	return []*schema.ResourceData{d}, nil
 }
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -27,13 +26,11 @@ func resourceDefaultRoles() *schema.Resource {
 			"user": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"host": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "localhost",
 			},
 
@@ -41,9 +38,10 @@ func resourceDefaultRoles() *schema.Resource {
 				Type:     schema.TypeSet,
 				Required: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:      schema.TypeString,
+					StateFunc: normalizeRoleReference,
 				},
-				Set: schema.HashString,
+				Set: hashRoleReference,
 			},
 		},
 	}
@@ -63,7 +61,7 @@ func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, r
 	stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' DEFAULT ROLE ", user, host)
 
 	if len(roles) > 0 {
-		stmtSQL += fmt.Sprintf("'%s'", strings.Join(roles, "', '"))
+		stmtSQL += rolesSQL(roles)
 	} else {
 		stmtSQL += "NONE"
 	}
@@ -104,7 +102,7 @@ func CreateDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.Errorf("failed to create user default roles: %v", err)
 	}
 
-	d.SetId(fmt.Sprintf("%s@%s", user, host))
+	d.SetId(formatUserHostId(user, host))
 
 	return nil
 }
@@ -118,9 +116,30 @@ func UpdateDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+
+	if d.HasChanges("user", "host") {
+		// A renamed account keeps its default roles. If the old account still exists, the default
+		// roles move from it to the new one.
+		oldUser, _ := d.GetChange("user")
+		oldHost, _ := d.GetChange("host")
+		exists, err := userExists(ctx, db, oldUser.(string), oldHost.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if exists {
+			if err := alterUserDefaultRoles(ctx, db, oldUser.(string), oldHost.(string), []string{}); err != nil {
+				return diag.Errorf("failed to remove user default roles: %v", err)
+			}
+			if err := alterUserDefaultRoles(ctx, db, user, host, getRolesFromData(d)); err != nil {
+				return diag.Errorf("failed to update user default roles: %v", err)
+			}
+		}
+		d.SetId(formatUserHostId(user, host))
+	}
+
 	if d.HasChange("roles") {
-		user := d.Get("user").(string)
-		host := d.Get("host").(string)
 		roles := getRolesFromData(d)
 
 		if err := alterUserDefaultRoles(ctx, db, user, host, roles); err != nil {
@@ -140,7 +159,7 @@ func ReadDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
-	stmtSQL := "SELECT default_role_user FROM mysql.default_roles WHERE user = ? AND host = ?"
+	stmtSQL := "SELECT default_role_user, default_role_host FROM mysql.default_roles WHERE user = ? AND host = ?"
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL)
 
@@ -152,12 +171,12 @@ func ReadDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interfac
 
 	var defaultRoles = make([]string, 0)
 	for rows.Next() {
-		var role string
-		err := rows.Scan(&role)
+		var role UserOrRole
+		err := rows.Scan(&role.Name, &role.Host)
 		if err != nil {
 			return diag.Errorf("failed scanning default roles: %v", err)
 		}
-		defaultRoles = append(defaultRoles, role)
+		defaultRoles = append(defaultRoles, formatRoleReference(role))
 	}
 
 	if rows.Err() != nil {
@@ -191,14 +210,13 @@ func DeleteDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func ImportDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	userHost := strings.SplitN(d.Id(), "@", 2)
-
-	if len(userHost) != 2 {
+	user, host, ok := parseUserHostId(d.Id())
+	if !ok {
 		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
 	}
 
-	d.Set("user", userHost[0])
-	d.Set("host", userHost[1])
+	d.Set("user", user)
+	d.Set("host", host)
 
 	readDiags := ReadDefaultRoles(ctx, d, meta)
 	for _, readDiag := range readDiags {
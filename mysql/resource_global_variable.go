@@ -20,7 +20,7 @@ func resourceGlobalVariable() *schema.Resource {
 		UpdateContext: CreateOrUpdateGlobalVariable,
 		DeleteContext: DeleteGlobalVariable,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: ImportGlobalVariable,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -119,3 +119,22 @@ func DeleteGlobalVariable(ctx context.Context, d *schema.ResourceData, meta inte
 
 	return nil
 }
+
+var kGlobalVariableNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+func ImportGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name := d.Id()
+	if !kGlobalVariableNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("wrong ID format %q (expected VARIABLE_NAME made of letters, digits, _ and .)", name)
+	}
+
+	readDiags := ReadGlobalVariable(ctx, d, meta)
+	if readDiags.HasError() {
+		return nil, fmt.Errorf("failed reading global variable: %v", readDiags)
+	}
+	if d.Get("name").(string) == "" {
+		return nil, fmt.Errorf("global variable %s does not exist", name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -7,14 +7,15 @@ import (
 	"log"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
-	"unicode"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 type ObjectT string
@@ -93,10 +94,21 @@ type TablePrivilegeGrant struct {
 	Grant      bool
 	UserOrRole UserOrRole
 	TLSOption  string
+	// RevokedDatabases are databases excluded from a global grant using partial revokes.
+	RevokedDatabases []string
+	// RevokedPrivileges are the privileges revoked on each of RevokedDatabases, as read from the server.
+	RevokedPrivileges map[string][]string
+	// TableInclude and TableExclude select the tables of Database the grant is made on separately.
+	TableInclude []string
+	TableExclude []string
 }
 
 func (t *TablePrivilegeGrant) GetId() string {
-	return fmt.Sprintf("%s:%s:%s", t.UserOrRole.IDString(), t.GetDatabase(), t.GetTable())
+	table := t.Table
+	if table == "" {
+		table = "*"
+	}
+	return fmt.Sprintf("%s:%s:%s", formatAccountId(t.UserOrRole), escapeIdPart(t.Database), escapeIdPart(table))
 }
 
 func (t *TablePrivilegeGrant) GetUserOrRole() UserOrRole {
@@ -180,6 +192,86 @@ func (t *TablePrivilegeGrant) SQLPartialRevokePrivilegesStatement(privilegesToRe
 	return fmt.Sprintf("REVOKE %s ON %s.%s FROM %s", strings.Join(privilegesToRevoke, ", "), t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())
 }
 
+// Privileges that exist on database level and can therefore be partially revoked from a global grant.
+var kDatabaseLevelPrivileges = map[string]bool{
+	"ALL PRIVILEGES": true, "ALTER": true, "ALTER ROUTINE": true, "CREATE": true, "CREATE ROUTINE": true,
+	"CREATE TEMPORARY TABLES": true, "CREATE VIEW": true, "DELETE": true, "DROP": true, "EVENT": true,
+	"EXECUTE": true, "INDEX": true, "INSERT": true, "LOCK TABLES": true, "REFERENCES": true, "SELECT": true,
+	"SHOW VIEW": true, "TRIGGER": true, "UPDATE": true,
+}
+
+func (t *TablePrivilegeGrant) databaseLevelPrivileges() []string {
+	privs := []string{}
+	for _, priv := range t.Privileges {
+		if kDatabaseLevelPrivileges[priv] {
+			privs = append(privs, priv)
+		}
+	}
+	return privs
+}
+
+// SQLRevokeOnDatabaseStatement excludes the database from a global grant. It needs partial_revokes enabled.
+func (t *TablePrivilegeGrant) SQLRevokeOnDatabaseStatement(database string) string {
+	privs := t.databaseLevelPrivileges()
+	if len(privs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("REVOKE %s ON %s.* FROM %s", strings.Join(privs, ", "), quoteIdentifier(database), t.UserOrRole.SQLString())
+}
+
+// fullyRevokedDatabases returns the revoked databases on which all database level privileges of the
+// grant are revoked. A database on which some of them were granted again is drift, so it's left out
+// and revoked again on the next apply.
+func (t *TablePrivilegeGrant) fullyRevokedDatabases() []string {
+	if t.RevokedPrivileges == nil {
+		return t.RevokedDatabases
+	}
+	expected := t.databaseLevelPrivileges()
+	if slices.Contains(expected, "ALL PRIVILEGES") {
+		expected = []string{}
+		for privilege := range kDatabaseLevelPrivileges {
+			if privilege != "ALL PRIVILEGES" {
+				expected = append(expected, privilege)
+			}
+		}
+	}
+
+	databases := []string{}
+	for _, database := range t.RevokedDatabases {
+		revoked := t.RevokedPrivileges[database]
+		if len(differenceOf(expected, revoked)) > 0 && !slices.Contains(revoked, "ALL PRIVILEGES") {
+			log.Printf("[DEBUG] Only %v of %v are revoked on %s for %s", revoked, expected, database, t.UserOrRole.SQLString())
+			continue
+		}
+		databases = append(databases, database)
+	}
+	return databases
+}
+
+// SQLGrantOnDatabaseStatement lifts a partial revoke made by SQLRevokeOnDatabaseStatement.
+func (t *TablePrivilegeGrant) SQLGrantOnDatabaseStatement(database string) string {
+	privs := t.databaseLevelPrivileges()
+	if len(privs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("GRANT %s ON %s.* TO %s", strings.Join(privs, ", "), quoteIdentifier(database), t.UserOrRole.SQLString())
+}
+
+// SQLGrantColumnsStatement grants privileges on just the given columns, e.g. SELECT(`a`, `b`).
+func (t *TablePrivilegeGrant) SQLGrantColumnsStatement(columnPrivileges map[string][]string) string {
+	stmtSql := fmt.Sprintf("GRANT %s ON %s.%s TO %s", columnPrivilegesSQL(columnPrivileges), t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())
+	if t.Grant {
+		stmtSql += " WITH GRANT OPTION"
+	}
+	return stmtSql
+}
+
+// SQLRevokeColumnsStatement revokes privileges on just the given columns. Unlike a partial revoke,
+// it keeps the grant option, which is shared by all privileges on the table.
+func (t *TablePrivilegeGrant) SQLRevokeColumnsStatement(columnPrivileges map[string][]string) string {
+	return fmt.Sprintf("REVOKE %s ON %s.%s FROM %s", columnPrivilegesSQL(columnPrivileges), t.GetDatabase(), t.GetTable(), t.UserOrRole.SQLString())
+}
+
 type ProcedurePrivilegeGrant struct {
 	Database     string
 	ObjectT      ObjectT
@@ -191,7 +283,8 @@ type ProcedurePrivilegeGrant struct {
 }
 
 func (t *ProcedurePrivilegeGrant) GetId() string {
-	return fmt.Sprintf("%s:%s:%s", t.UserOrRole.IDString(), t.GetDatabase(), t.GetCallableName())
+	database := strings.Trim(t.Database, "`")
+	return fmt.Sprintf("%s:%s %s:%s", formatAccountId(t.UserOrRole), t.ObjectT, escapeIdPart(database), escapeIdPart(t.CallableName))
 }
 
 func (t *ProcedurePrivilegeGrant) GetUserOrRole() UserOrRole {
@@ -266,7 +359,7 @@ type RoleGrant struct {
 }
 
 func (t *RoleGrant) GetId() string {
-	return fmt.Sprintf("%s", t.UserOrRole.IDString())
+	return fmt.Sprintf("%s;r", formatAccountId(t.UserOrRole))
 }
 
 func (t *RoleGrant) GetUserOrRole() UserOrRole {
@@ -278,7 +371,7 @@ func (t *RoleGrant) GrantOption() bool {
 }
 
 func (t *RoleGrant) SQLGrantStatement() string {
-	stmtSql := fmt.Sprintf("GRANT '%s' TO %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())
+	stmtSql := fmt.Sprintf("GRANT %s TO %s", rolesSQL(t.Roles), t.UserOrRole.SQLString())
 	if t.TLSOption != "" && strings.ToLower(t.TLSOption) != "none" {
 		stmtSql += fmt.Sprintf(" REQUIRE %s", t.TLSOption)
 	}
@@ -289,7 +382,7 @@ func (t *RoleGrant) SQLGrantStatement() string {
 }
 
 func (t *RoleGrant) SQLRevokeStatement() string {
-	return fmt.Sprintf("REVOKE '%s' FROM %s", strings.Join(t.Roles, "', '"), t.UserOrRole.SQLString())
+	return fmt.Sprintf("REVOKE %s FROM %s", rolesSQL(t.Roles), t.UserOrRole.SQLString())
 }
 
 func (t *RoleGrant) GetRoles() []string {
@@ -310,7 +403,7 @@ func (t *RoleGrant) ConflictsWithGrant(other MySQLGrant) bool {
 }
 
 func resourceGrant() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: CreateGrant,
 		UpdateContext: UpdateGrant,
 		ReadContext:   ReadGrant,
@@ -318,12 +411,13 @@ func resourceGrant() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportGrant,
 		},
+		CustomizeDiff: customizeGrantDiff,
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ForceNew:      true,
 				ConflictsWith: []string{"role"},
 			},
 
@@ -332,12 +426,12 @@ func resourceGrant() *schema.Resource {
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"user", "host"},
+				StateFunc:     normalizeRoleReference,
 			},
 
 			"host": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ForceNew:      true,
 				Default:       "localhost",
 				ConflictsWith: []string{"role"},
 			},
@@ -363,13 +457,36 @@ func resourceGrant() *schema.Resource {
 				Set:      schema.HashString,
 			},
 
+			"column_privileges": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"roles"},
+				Set:           hashColumnPrivilege,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"privilege": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"SELECT", "INSERT", "UPDATE", "REFERENCES"}, true),
+						},
+						"columns": {
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      hashColumnName,
+						},
+					},
+				},
+			},
+
 			"roles": {
 				Type:          schema.TypeSet,
 				Optional:      true,
 				ForceNew:      true,
 				ConflictsWith: []string{"privileges"},
-				Elem:          &schema.Schema{Type: schema.TypeString},
-				Set:           schema.HashString,
+				Elem:          &schema.Schema{Type: schema.TypeString, StateFunc: normalizeRoleReference},
+				Set:           hashRoleReference,
 			},
 
 			"grant": {
@@ -379,6 +496,37 @@ func resourceGrant() *schema.Resource {
 				Default:  false,
 			},
 
+			"revoked_databases": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"roles"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+			},
+
+			"table_include": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"roles", "column_privileges", "revoked_databases"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+			},
+
+			"table_exclude": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"roles", "column_privileges", "revoked_databases"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				Set:           schema.HashString,
+			},
+
+			"tables": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
 			"tls_option": {
 				Type:       schema.TypeString,
 				Optional:   true,
@@ -388,6 +536,143 @@ func resourceGrant() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(resourceGrantV0(), resourceGrantStateUpgradeV0)
+	return r
+}
+
+// Column names are case insensitive in MySQL and SHOW GRANTS returns them uppercased,
+// so column privileges are hashed case insensitively as well.
+func hashColumnName(v interface{}) int {
+	return schema.HashString(strings.ToLower(v.(string)))
+}
+
+func hashColumnPrivilege(v interface{}) int {
+	m := v.(map[string]interface{})
+	columns := []string{}
+	if set, ok := m["columns"].(*schema.Set); ok {
+		for _, column := range setToArray(set) {
+			columns = append(columns, strings.ToLower(column))
+		}
+	}
+	sort.Strings(columns)
+	privilege, _ := m["privilege"].(string)
+	return schema.HashString(strings.ToUpper(privilege) + "(" + strings.Join(columns, ",") + ")")
+}
+
+// resourceGrantV0 is the schema of mysql_grant at version 0.
+func resourceGrantV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user":     {Type: schema.TypeString, Optional: true},
+			"role":     {Type: schema.TypeString, Optional: true},
+			"host":     {Type: schema.TypeString, Optional: true},
+			"database": {Type: schema.TypeString, Optional: true},
+			"table":    {Type: schema.TypeString, Optional: true},
+			"privileges": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"grant":      {Type: schema.TypeBool, Optional: true},
+			"tls_option": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceGrantStateUpgradeV0 rewrites IDs like user@host:`db`:`table` to the escaped format of GetId.
+func resourceGrantStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	d := resourceGrant().Data(nil)
+	for _, key := range []string{"user", "role", "host", "database", "table", "privileges", "roles", "grant", "tls_option"} {
+		if value, ok := rawState[key]; ok && value != nil {
+			if err := d.Set(key, value); err != nil {
+				return nil, fmt.Errorf("failed upgrading grant %v: %w", rawState["id"], err)
+			}
+		}
+	}
+
+	grant, diags := parseResourceFromData(d)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed upgrading grant %v: %v", rawState["id"], diags)
+	}
+	log.Printf("[DEBUG] Upgrading grant ID %v to %s", rawState["id"], grant.GetId())
+	rawState["id"] = grant.GetId()
+	return rawState, nil
+}
+
+func customizeGrantDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("database") && d.NewValueKnown("table") {
+		if err := validateGrantAttributes(d); err != nil {
+			return err
+		}
+	}
+	if err := validateGrantPrivileges(ctx, d, meta); err != nil {
+		return err
+	}
+	if err := validateGrantColumns(ctx, d, meta); err != nil {
+		return err
+	}
+	return resolveGrantTables(ctx, d, meta)
+}
+
+// validateGrantColumns checks at plan time that every column in column_privileges exists. Tables that
+// don't exist yet (e.g. created in the same apply) are skipped; MySQL checks them when granting.
+func validateGrantColumns(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("column_privileges") || !d.NewValueKnown("column_privileges") ||
+		!d.NewValueKnown("database") || !d.NewValueKnown("table") {
+		return nil
+	}
+	columnPrivileges := expandColumnPrivileges(d.Get("column_privileges"))
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+	if len(columnPrivileges) == 0 || table == "*" {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT COLUMN_NAME FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", database, table)
+	if err != nil {
+		return fmt.Errorf("failed reading columns of %s.%s: %w", database, table, err)
+	}
+	defer rows.Close()
+
+	existing := map[string]bool{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return fmt.Errorf("failed reading columns of %s.%s: %w", database, table, err)
+		}
+		existing[strings.ToLower(column)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed reading columns of %s.%s: %w", database, table, err)
+	}
+	if len(existing) == 0 {
+		log.Printf("[DEBUG] Table %s.%s not found, skipping validation of column_privileges", database, table)
+		return nil
+	}
+
+	missing := []string{}
+	for privilege, columns := range columnPrivileges {
+		for _, column := range columns {
+			if !existing[strings.ToLower(column)] {
+				missing = append(missing, fmt.Sprintf("%s(%s)", privilege, column))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("column_privileges refer to columns that don't exist in %s.%s: %s", database, table, strings.Join(missing, ", "))
+	}
+	return nil
 }
 
 func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
@@ -401,6 +686,38 @@ func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
 var kReProcedureWithoutDatabase = regexp.MustCompile(`(?i)^(function|procedure) ([^.]*)$`)
 var kReProcedureWithDatabase = regexp.MustCompile(`(?i)^(function|procedure) ([^.]*)\.([^.]*)$`)
 
+// validateGrantAttributes checks the combinations of attributes a grant can't be built from. It's run
+// at plan time as well, so they're rejected before anything is applied.
+func validateGrantAttributes(d interface{ Get(string) interface{} }) error {
+	if d.Get("roles").(*schema.Set).Len() > 0 {
+		return nil
+	}
+	database := d.Get("database").(string)
+	table := d.Get("table").(string)
+
+	if d.Get("column_privileges").(*schema.Set).Len() > 0 {
+		if m := kReProcedureWithDatabase.FindStringSubmatch(database); m != nil {
+			return fmt.Errorf("column_privileges can't be used with %s grants", m[1])
+		}
+		if m := kReProcedureWithoutDatabase.FindStringSubmatch(database); m != nil {
+			return fmt.Errorf("column_privileges can't be used with %s grants", m[1])
+		}
+		if table == "*" {
+			return fmt.Errorf("column_privileges can only be used with a table")
+		}
+		if hasColumnPrivilegeStrings(setToArray(d.Get("privileges"))) {
+			return fmt.Errorf("column privileges must be set either in privileges or in column_privileges, not both")
+		}
+	}
+	if d.Get("revoked_databases").(*schema.Set).Len() > 0 && (database != "*" || table != "*") {
+		return fmt.Errorf("revoked_databases can only be used with database and table set to *")
+	}
+	if (d.Get("table_include").(*schema.Set).Len() > 0 || d.Get("table_exclude").(*schema.Set).Len() > 0) && (database == "*" || table != "*") {
+		return fmt.Errorf("table_include and table_exclude can only be used with a database and table set to *")
+	}
+	return nil
+}
+
 func parseResourceFromData(d *schema.ResourceData) (MySQLGrant, diag.Diagnostics) {
 
 	// Step 1: Parse the user/role
@@ -417,21 +734,22 @@ func parseResourceFromData(d *schema.ResourceData) (MySQLGrant, diag.Diagnostics
 			Host: hostAttr.(string),
 		}
 	} else if roleOk && roleAttr.(string) != "" {
-		userOrRole = UserOrRole{
-			Name: roleAttr.(string),
-		}
+		userOrRole = parseRoleReference(roleAttr.(string))
 	} else {
 		return nil, diag.Errorf("One of user/host or role is required")
 	}
 
 	// Step 2: Get generic attributes
+	if err := validateGrantAttributes(d); err != nil {
+		return nil, diag.FromErr(err)
+	}
 	database := d.Get("database").(string)
 	tlsOption := d.Get("tls_option").(string)
 	grantOption := d.Get("grant").(bool)
 
 	// Step 3a: If `roles` is specified, we have a role grant
 	if attr, ok := d.GetOk("roles"); ok {
-		roles := setToArray(attr)
+		roles := normalizeRoleReferences(setToArray(attr))
 		return &RoleGrant{
 			Roles:      roles,
 			Grant:      grantOption,
@@ -472,15 +790,31 @@ func parseResourceFromData(d *schema.ResourceData) (MySQLGrant, diag.Diagnostics
 
 	// Step 3c. Otherwise, we have a table grant
 	privsList := setToArray(d.Get("privileges"))
+	table := d.Get("table").(string)
+
+	for privilege, columns := range expandColumnPrivileges(d.Get("column_privileges")) {
+		privsList = append(privsList, columnPrivilegeString(privilege, columns))
+	}
 	privileges := normalizePerms(privsList)
 
+	revokedDatabases := setToArray(d.Get("revoked_databases"))
+	sort.Strings(revokedDatabases)
+
+	tableInclude := setToArray(d.Get("table_include"))
+	tableExclude := setToArray(d.Get("table_exclude"))
+	sort.Strings(tableInclude)
+	sort.Strings(tableExclude)
+
 	return &TablePrivilegeGrant{
-		Database:   database,
-		Table:      d.Get("table").(string),
-		Privileges: privileges,
-		Grant:      grantOption,
-		UserOrRole: userOrRole,
-		TLSOption:  tlsOption,
+		Database:         database,
+		Table:            table,
+		Privileges:       privileges,
+		Grant:            grantOption,
+		UserOrRole:       userOrRole,
+		TLSOption:        tlsOption,
+		RevokedDatabases: revokedDatabases,
+		TableInclude:     tableInclude,
+		TableExclude:     tableExclude,
 	}, nil
 }
 
@@ -492,7 +826,7 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	// Parse the ResourceData
 	grant, diagErr := parseResourceFromData(d)
-	if err != nil {
+	if diagErr != nil {
 		return diagErr
 	}
 
@@ -509,6 +843,15 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	// This is necessary so that the conflicting grant check is correct with respect to other grants being created
 	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
 	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
+	defer invalidateUserGrants(db, grant.GetUserOrRole())
+
+	if tableGrant, ok := grant.(*TablePrivilegeGrant); ok && tableGrant.HasTablePatterns() {
+		if diags := createTablePatternGrant(ctx, db, d, tableGrant); diags.HasError() {
+			return diags
+		}
+		invalidateUserGrants(db, grant.GetUserOrRole())
+		return ReadGrant(ctx, d, meta)
+	}
 
 	// Check to see if there are existing roles that might be clobbered by this grant
 	conflictingGrant, err := getMatchingGrant(ctx, db, grant)
@@ -528,6 +871,14 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	}
 
 	d.SetId(grant.GetId())
+
+	if tableGrant, ok := grant.(*TablePrivilegeGrant); ok {
+		if err := revokeDatabases(ctx, db, tableGrant, tableGrant.RevokedDatabases); err != nil {
+			return diag.Errorf("failed revoking databases: %v", err)
+		}
+	}
+
+	invalidateUserGrants(db, grant.GetUserOrRole())
 	return ReadGrant(ctx, d, meta)
 }
 
@@ -542,6 +893,10 @@ func ReadGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 		return diagErr
 	}
 
+	if tableGrant, ok := grantFromTf.(*TablePrivilegeGrant); ok && tableGrant.HasTablePatterns() {
+		return readTablePatternGrant(ctx, db, d, tableGrant)
+	}
+
 	grantFromDb, err := getMatchingGrant(ctx, db, grantFromTf)
 	if err != nil {
 		return diag.Errorf("ReadGrant - getting all grants failed: %v", err)
@@ -567,6 +922,27 @@ func UpdateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		return diag.Errorf("failed getting user or role: %v", err)
 	}
 
+	if grant, diagErr := parseResourceFromData(d); diagErr == nil {
+		defer invalidateUserGrants(db, grant.GetUserOrRole())
+	}
+
+	if d.HasChanges("user", "host") {
+		moved, diags := updateGrantAccount(ctx, db, d, meta)
+		if moved || diags.HasError() {
+			return diags
+		}
+	}
+
+	if d.HasChanges("table_include", "table_exclude", "tables", "privileges") {
+		grant, diagErr := parseResourceFromData(d)
+		if diagErr != nil {
+			return diagErr
+		}
+		if tableGrant, ok := grant.(*TablePrivilegeGrant); ok && tableGrant.HasTablePatterns() {
+			return updateTablePatternGrant(ctx, db, d, tableGrant)
+		}
+	}
+
 	if d.HasChange("privileges") {
 		grant, diagErr := parseResourceFromData(d)
 		if diagErr != nil {
@@ -579,50 +955,332 @@ func UpdateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		}
 	}
 
+	if d.HasChange("column_privileges") {
+		grant, diagErr := parseResourceFromData(d)
+		if diagErr != nil {
+			return diagErr
+		}
+
+		err = updateColumnPrivileges(ctx, db, d, grant)
+		if err != nil {
+			return diag.Errorf("failed updating column privileges: %v", err)
+		}
+	}
+
+	if d.HasChanges("privileges", "revoked_databases") {
+		grant, diagErr := parseResourceFromData(d)
+		if diagErr != nil {
+			return diagErr
+		}
+
+		err = updateRevokedDatabases(ctx, db, d, grant)
+		if err != nil {
+			return diag.Errorf("failed updating revoked databases: %v", err)
+		}
+	}
+
 	return nil
 }
 
-func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant MySQLGrant) error {
-	oldPrivsIf, newPrivsIf := d.GetChange("privileges")
-	oldPrivs := oldPrivsIf.(*schema.Set)
-	newPrivs := newPrivsIf.(*schema.Set)
-	grantIfs := newPrivs.Difference(oldPrivs).List()
-	revokeIfs := oldPrivs.Difference(newPrivs).List()
+// updateGrantAccount handles a change of user or host. If the old account is gone, it was renamed
+// and the grant followed it, so only the ID changes and the rest of the update applies as usual.
+// Otherwise the grant moves: it is revoked from the old account and created for the new one, in
+// which case it returns true.
+func updateGrantAccount(ctx context.Context, db *sql.DB, d *schema.ResourceData, meta interface{}) (bool, diag.Diagnostics) {
+	grant, diagErr := parseResourceFromData(d)
+	if diagErr != nil {
+		return false, diagErr
+	}
 
-	// Normalize the privileges to revoke
-	privsToRevoke := []string{}
-	for _, revokeIf := range revokeIfs {
-		privsToRevoke = append(privsToRevoke, revokeIf.(string))
+	oldUser, _ := d.GetChange("user")
+	oldHost, _ := d.GetChange("host")
+	oldAccount := UserOrRole{Name: oldUser.(string), Host: oldHost.(string)}
+	exists, err := userExists(ctx, db, oldAccount.Name, oldAccount.Host)
+	if err != nil {
+		return false, diag.FromErr(err)
+	}
+	if !exists {
+		log.Printf("[DEBUG] %s was renamed to %s", oldAccount.IDString(), grant.GetUserOrRole().IDString())
+		d.SetId(grant.GetId())
+		return false, nil
 	}
-	privsToRevoke = normalizePerms(privsToRevoke)
 
-	// Do a partial revoke of anything that has been removed
-	if len(privsToRevoke) > 0 {
-		partialRevoker, ok := grant.(PrivilegesPartiallyRevocable)
-		if !ok {
-			return fmt.Errorf("grant does not support partial privilege revokes")
+	oldGrant := grantForAccount(grant, oldAccount)
+	defer invalidateUserGrants(db, oldAccount)
+	if tableGrant, ok := oldGrant.(*TablePrivilegeGrant); ok && tableGrant.HasTablePatterns() {
+		oldTables, _ := d.GetChange("tables")
+		for _, table := range setToArray(oldTables) {
+			if err := execIgnoringMissingGrant(ctx, db, tableGrant.forTable(table).SQLRevokeStatement()); err != nil {
+				return true, diag.FromErr(err)
+			}
+		}
+	} else {
+		if _, ok := oldGrant.(*RoleGrant); !ok {
+			// Revoke what the old account has on the object, which may differ from the new privileges.
+			dbGrant, err := getMatchingGrant(ctx, db, oldGrant)
+			if err != nil {
+				return true, diag.Errorf("failed showing grants: %v", err)
+			}
+			if dbGrant == nil {
+				dbGrant = oldGrant
+			}
+			oldGrant = dbGrant
+		}
+		if err := execIgnoringMissingGrant(ctx, db, oldGrant.SQLRevokeStatement()); err != nil {
+			return true, diag.FromErr(err)
+		}
+	}
+
+	return true, CreateGrant(ctx, d, meta)
+}
+
+// grantForAccount returns a copy of grant for another user or role.
+func grantForAccount(grant MySQLGrant, userOrRole UserOrRole) MySQLGrant {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		copied := *g
+		copied.UserOrRole = userOrRole
+		return &copied
+	case *ProcedurePrivilegeGrant:
+		copied := *g
+		copied.UserOrRole = userOrRole
+		return &copied
+	case *RoleGrant:
+		copied := *g
+		copied.UserOrRole = userOrRole
+		return &copied
+	}
+	return grant
+}
+
+// updateRevokedDatabases runs after updatePrivileges, because granting privileges globally lifts
+// their partial revokes. If privileges changed, all revoked databases are revoked again.
+func updateRevokedDatabases(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant MySQLGrant) error {
+	tableGrant, ok := grant.(*TablePrivilegeGrant)
+	if !ok {
+		return nil
+	}
+
+	oldDatabasesIf, newDatabasesIf := d.GetChange("revoked_databases")
+	oldDatabases := oldDatabasesIf.(*schema.Set)
+	newDatabases := newDatabasesIf.(*schema.Set)
+
+	for _, database := range setToArray(oldDatabases.Difference(newDatabases)) {
+		stmtSQL := tableGrant.SQLGrantOnDatabaseStatement(database)
+		if stmtSQL == "" {
+			continue
+		}
+		log.Printf("[DEBUG] SQL to lift partial revoke: %s", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return err
 		}
-		sqlCommand := partialRevoker.SQLPartialRevokePrivilegesStatement(privsToRevoke)
-		log.Printf("[DEBUG] SQL for partial revoke: %s", sqlCommand)
+	}
 
-		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+	toRevoke := setToArray(newDatabases.Difference(oldDatabases))
+	if d.HasChange("privileges") {
+		toRevoke = setToArray(newDatabases)
+	}
+	sort.Strings(toRevoke)
+	return revokeDatabases(ctx, db, tableGrant, toRevoke)
+}
+
+func revokeDatabases(ctx context.Context, db *sql.DB, grant *TablePrivilegeGrant, databases []string) error {
+	for _, database := range databases {
+		stmtSQL := grant.SQLRevokeOnDatabaseStatement(database)
+		if stmtSQL == "" {
+			return fmt.Errorf("none of the privileges %v can be revoked on database %s", grant.Privileges, database)
+		}
+		log.Printf("[DEBUG] SQL for partial revoke: %s", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			if isNonExistingGrant(err) {
+				return fmt.Errorf("%w (revoked_databases needs partial_revokes=ON)", err)
+			}
 			return err
 		}
 	}
+	return nil
+}
+
+// updatePrivileges changes the privileges by the minimal delta. New privileges are granted before old ones
+// are revoked, so privileges kept in the configuration are held during the whole update.
+func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant MySQLGrant) error {
+	oldPrivsIf, newPrivsIf := d.GetChange("privileges")
+	statements, rollbacks, err := privilegeUpdateStatements(grant, setToArray(oldPrivsIf), setToArray(newPrivsIf))
+	if err != nil {
+		return err
+	}
+	return execWithRollback(ctx, db, statements, rollbacks)
+}
 
-	// Do a full grant if anything has been added
-	if len(grantIfs) > 0 {
-		sqlCommand := grant.SQLGrantStatement()
-		log.Printf("[DEBUG] SQL to re-grant privileges: %s", sqlCommand)
+// privilegeUpdateStatements returns the statements changing the privileges of grant from oldPrivileges
+// to newPrivileges, and for each of them a statement undoing it.
+func privilegeUpdateStatements(grant MySQLGrant, oldPrivileges, newPrivileges []string) (statements, rollbacks []string, err error) {
+	oldPrivileges = normalizePerms(oldPrivileges)
+	newPrivileges = normalizePerms(newPrivileges)
+	toGrant, toRevoke := privilegeDelta(oldPrivileges, newPrivileges)
 
-		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
+	grantStatement := func(privileges []string) (string, error) {
+		privilegeGrant, err := grantWithPrivileges(grant, privileges)
+		if err != nil {
+			return "", err
+		}
+		return privilegeGrant.SQLGrantStatement(), nil
+	}
+	revokeStatement := func(privileges []string) (string, error) {
+		privilegeGrant, err := grantWithPrivileges(grant, privileges)
+		if err != nil {
+			return "", err
+		}
+		// grant is ForceNew, so the grant option is kept while updating privileges.
+		switch g := privilegeGrant.(type) {
+		case *TablePrivilegeGrant:
+			g.Grant = false
+		case *ProcedurePrivilegeGrant:
+			g.Grant = false
+		}
+		partialRevoker, ok := privilegeGrant.(PrivilegesPartiallyRevocable)
+		if !ok {
+			return "", fmt.Errorf("grant does not support partial privilege revokes")
+		}
+		return partialRevoker.SQLPartialRevokePrivilegesStatement(privileges), nil
+	}
+	add := func(privileges []string, forward, backward func([]string) (string, error)) error {
+		if len(privileges) == 0 {
+			return nil
+		}
+		statement, err := forward(privileges)
+		if err != nil {
+			return err
+		}
+		rollback, err := backward(privileges)
+		if err != nil {
 			return err
 		}
+		statements = append(statements, statement)
+		rollbacks = append(rollbacks, rollback)
+		return nil
+	}
+
+	revokeFirst := false
+	switch {
+	case containsAllPrivilege(oldPrivileges) && !containsAllPrivilege(newPrivileges):
+		// Revoking ALL PRIVILEGES would also revoke the privileges granted just before, so there is
+		// no way around revoking first.
+		revokeFirst = true
+	case containsAllPrivilege(newPrivileges):
+		// ALL PRIVILEGES includes every table privilege, revoking them would make holes into it.
+		// Column privileges are separate from it.
+		toRevoke = slices.DeleteFunc(toRevoke, func(privilege string) bool { return !kReColumnPrivilege.MatchString(privilege) })
+	}
+
+	if revokeFirst {
+		if err := add(toRevoke, revokeStatement, grantStatement); err != nil {
+			return nil, nil, err
+		}
+		if err := add(toGrant, grantStatement, revokeStatement); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if err := add(toGrant, grantStatement, revokeStatement); err != nil {
+			return nil, nil, err
+		}
+		if err := add(toRevoke, revokeStatement, grantStatement); err != nil {
+			return nil, nil, err
+		}
 	}
+	return statements, rollbacks, nil
+}
+
+// privilegeDelta returns the privileges in newPrivileges and not in oldPrivileges and vice versa.
+// Column privileges are compared per column, so a column removed from SELECT(a, b) revokes just SELECT(b).
+func privilegeDelta(oldPrivileges, newPrivileges []string) (toGrant, toRevoke []string) {
+	oldTablePrivileges, oldColumnPrivileges := splitColumnPrivileges(oldPrivileges)
+	newTablePrivileges, newColumnPrivileges := splitColumnPrivileges(newPrivileges)
+	columnsToRevoke, columnsToGrant := diffColumnPrivileges(oldColumnPrivileges, newColumnPrivileges)
 
+	withColumns := func(privileges []string, columnPrivileges map[string][]string) []string {
+		for privilege, columns := range columnPrivileges {
+			privileges = append(privileges, columnPrivilegeString(privilege, columns))
+		}
+		sort.Strings(privileges)
+		return privileges
+	}
+	return withColumns(differenceOf(newTablePrivileges, oldTablePrivileges), columnsToGrant),
+		withColumns(differenceOf(oldTablePrivileges, newTablePrivileges), columnsToRevoke)
+}
+
+// grantWithPrivileges returns a copy of grant with privileges instead of its own.
+func grantWithPrivileges(grant MySQLGrant, privileges []string) (MySQLGrant, error) {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		copied := *g
+		copied.Privileges = privileges
+		return &copied, nil
+	case *ProcedurePrivilegeGrant:
+		copied := *g
+		copied.Privileges = privileges
+		return &copied, nil
+	}
+	return nil, fmt.Errorf("grant does not support partial privilege revokes")
+}
+
+// execWithRollback runs statements in order. If one fails, the rollbacks of the statements that already
+// ran are run in reverse order, to restore the previous privileges on a best-effort basis.
+func execWithRollback(ctx context.Context, db *sql.DB, statements, rollbacks []string) error {
+	for i, stmtSQL := range statements {
+		log.Printf("[DEBUG] SQL to update privileges: %s", stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL)
+		if err == nil {
+			continue
+		}
+		// Revoking privileges that are gone already leaves the account as expected.
+		if strings.HasPrefix(stmtSQL, "REVOKE") && isNonExistingGrant(err) {
+			log.Printf("[WARN] Ignoring error of %s: %v", stmtSQL, err)
+			continue
+		}
+
+		err = fmt.Errorf("error running SQL (%s): %w", stmtSQL, err)
+		for j := i - 1; j >= 0; j-- {
+			log.Printf("[DEBUG] SQL to roll back privileges: %s", rollbacks[j])
+			if _, rollbackErr := db.ExecContext(ctx, rollbacks[j]); rollbackErr != nil {
+				log.Printf("[WARN] Failed rolling back with %s: %v", rollbacks[j], rollbackErr)
+				return fmt.Errorf("%w; rolling back with %s failed too, the privileges may be left partially updated: %v", err, rollbacks[j], rollbackErr)
+			}
+		}
+		return err
+	}
 	return nil
 }
 
+// updateColumnPrivileges grants and revokes only the columns that were added to or removed from
+// column_privileges, so the privileges on other columns are never interrupted.
+func updateColumnPrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant MySQLGrant) error {
+	tableGrant, ok := grant.(*TablePrivilegeGrant)
+	if !ok {
+		return nil
+	}
+
+	oldColumnsIf, newColumnsIf := d.GetChange("column_privileges")
+	statements, rollbacks := columnPrivilegeUpdateStatements(tableGrant, expandColumnPrivileges(oldColumnsIf), expandColumnPrivileges(newColumnsIf))
+	return execWithRollback(ctx, db, statements, rollbacks)
+}
+
+// columnPrivilegeUpdateStatements returns the statements changing the column privileges of grant, and
+// for each of them a statement undoing it. Like privilegeUpdateStatements, added columns are granted
+// before removed ones are revoked.
+func columnPrivilegeUpdateStatements(grant *TablePrivilegeGrant, oldPrivileges, newPrivileges map[string][]string) (statements, rollbacks []string) {
+	toRevoke, toGrant := diffColumnPrivileges(oldPrivileges, newPrivileges)
+	if len(toGrant) > 0 {
+		statements = append(statements, grant.SQLGrantColumnsStatement(toGrant))
+		rollbacks = append(rollbacks, grant.SQLRevokeColumnsStatement(toGrant))
+	}
+	if len(toRevoke) > 0 {
+		statements = append(statements, grant.SQLRevokeColumnsStatement(toRevoke))
+		rollbacks = append(rollbacks, grant.SQLGrantColumnsStatement(toRevoke))
+	}
+	return statements, rollbacks
+}
+
 func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -631,13 +1289,18 @@ func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	// Parse the grant from ResourceData
 	grant, diagErr := parseResourceFromData(d)
-	if err != nil {
+	if diagErr != nil {
 		return diagErr
 	}
 
 	// Acquire a lock for the user
 	grantCreateMutex.Lock(grant.GetUserOrRole().IDString())
 	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
+	defer invalidateUserGrants(db, grant.GetUserOrRole())
+
+	if tableGrant, ok := grant.(*TablePrivilegeGrant); ok && tableGrant.HasTablePatterns() {
+		return deleteTablePatternGrant(ctx, db, d, tableGrant)
+	}
 
 	sqlStatement := grant.SQLRevokeStatement()
 	log.Printf("[DEBUG] SQL to delete grant: %s", sqlStatement)
@@ -659,11 +1322,23 @@ func isNonExistingGrant(err error) bool {
 	return errorNumber == 1141 || errorNumber == 1147 || errorNumber == 1403
 }
 
+var kImportProcedureGrantRegex = regexp.MustCompile(`(?i)^([^@]*)@([^:]*):(PROCEDURE|FUNCTION) ([^.]+)\.([^@]+)(@)?$`)
+
 func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	userHostDatabaseTable := strings.Split(strings.TrimSuffix(d.Id(), ";r"), "@")
+	if m := kImportProcedureGrantRegex.FindStringSubmatch(d.Id()); m != nil {
+		return importProcedureGrant(ctx, d, meta, m)
+	}
+
+	userHostDatabaseTable := splitId(strings.TrimSuffix(d.Id(), ";r"), '@')
+	for i, part := range userHostDatabaseTable {
+		userHostDatabaseTable[i] = unescapeIdPart(part)
+	}
 
 	if len(userHostDatabaseTable) != 4 && len(userHostDatabaseTable) != 5 {
-		return nil, fmt.Errorf("wrong ID format %s - expected user@host@database@table (and optionally ending @ to signify grant option) where some parts can be empty)", d.Id())
+		return nil, fmt.Errorf("wrong ID format %s - expected user@host@database@table (and optionally ending @ to signify grant option) where some parts can be empty), or user@host:PROCEDURE database.procedure for procedure and function grants", d.Id())
+	}
+	if len(userHostDatabaseTable) == 5 && userHostDatabaseTable[4] != "" {
+		return nil, fmt.Errorf("wrong ID format %s - only a single trailing @ may follow the table to signify grant option", d.Id())
 	}
 
 	user := userHostDatabaseTable[0]
@@ -671,6 +1346,9 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	database := userHostDatabaseTable[2]
 	table := userHostDatabaseTable[3]
 	grantOption := len(userHostDatabaseTable) == 5
+	if user == "" {
+		return nil, fmt.Errorf("wrong ID format %s - user or role name must not be empty", d.Id())
+	}
 	userOrRole := UserOrRole{
 		Name: user,
 		Host: host,
@@ -683,6 +1361,9 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 			Grant:      grantOption,
 		}
 	} else {
+		if database == "" || table == "" {
+			return nil, fmt.Errorf("wrong ID format %s - database and table must not be empty, use * for all", d.Id())
+		}
 		desiredGrant = &TablePrivilegeGrant{
 			Database:   database,
 			Table:      table,
@@ -691,33 +1372,71 @@ func ImportGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 		}
 	}
 
+	foundGrant, err := findGrantToImport(ctx, meta, desiredGrant)
+	if err != nil {
+		return nil, err
+	}
+	res := resourceGrant().Data(nil)
+	setDataFromGrant(foundGrant, res)
+	if _, ok := desiredGrant.(*RoleGrant); ok {
+		/*
+			Import database and table for role grants literally for backwards compatibility.
+			Role grants do not have a database or table, but we still set them here to avoid
+			making existing resources to "force replacement".
+		*/
+		res.Set("database", database)
+		res.Set("table", table)
+	}
+	return []*schema.ResourceData{res}, nil
+}
+
+// importProcedureGrant imports IDs like user@host:PROCEDURE db.proc, optionally ending with @ to signify grant option.
+func importProcedureGrant(ctx context.Context, d *schema.ResourceData, meta interface{}, m []string) ([]*schema.ResourceData, error) {
+	user, host, objectT, database, callable := m[1], m[2], m[3], m[4], m[5]
+	if user == "" {
+		return nil, fmt.Errorf("wrong ID format %s - user or role name must not be empty", d.Id())
+	}
+
+	desiredGrant := &ProcedurePrivilegeGrant{
+		Database:     database,
+		ObjectT:      ObjectT(objectT),
+		CallableName: callable,
+		Grant:        m[6] != "",
+		UserOrRole: UserOrRole{
+			Name: user,
+			Host: host,
+		},
+	}
+
+	foundGrant, err := findGrantToImport(ctx, meta, desiredGrant)
+	if err != nil {
+		return nil, err
+	}
+	res := resourceGrant().Data(nil)
+	setDataFromGrant(foundGrant, res)
+	// Procedure grants are configured as database = "PROCEDURE db.proc", keep the casing from the ID.
+	res.Set("database", fmt.Sprintf("%s %s.%s", objectT, database, callable))
+	res.Set("table", "*")
+	return []*schema.ResourceData{res}, nil
+}
+
+func findGrantToImport(ctx context.Context, meta interface{}, desiredGrant MySQLGrant) (MySQLGrant, error) {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return nil, fmt.Errorf("got error while getting database from meta: %w", err)
 	}
 
-	grants, err := showUserGrants(ctx, db, userOrRole)
+	grants, err := showUserGrants(ctx, db, desiredGrant.GetUserOrRole())
 	if err != nil {
 		return nil, fmt.Errorf("failed to showUserGrants in import: %w", err)
 	}
 	for _, foundGrant := range grants {
 		if foundGrant.ConflictsWithGrant(desiredGrant) {
-			res := resourceGrant().Data(nil)
-			setDataFromGrant(foundGrant, res)
-			if _, ok := desiredGrant.(*RoleGrant); ok {
-				/*
-					Import database and table for role grants literally for backwards compatibility.
-					Role grants do not have a database or table, but we still set them here to avoid
-					making existing resources to "force replacement".
-				*/
-				res.Set("database", database)
-				res.Set("table", table)
-			}
-			return []*schema.ResourceData{res}, nil
+			return foundGrant, nil
 		}
 	}
 
-	return nil, fmt.Errorf("failed to find the grant to import: %v -- found %#v", userHostDatabaseTable, grants)
+	return nil, fmt.Errorf("failed to find the grant to import: %#v -- found %#v", desiredGrant, grants)
 }
 
 // setDataFromGrant copies the values from MySQLGrant to the schema.ResourceData
@@ -729,6 +1448,7 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 	if tableGrant, ok := grant.(*TablePrivilegeGrant); ok {
 		d.Set("grant", grant.GrantOption())
 		d.Set("tls_option", tableGrant.TLSOption)
+		d.Set("revoked_databases", tableGrant.fullyRevokedDatabases())
 
 	} else if procedureGrant, ok := grant.(*ProcedurePrivilegeGrant); ok {
 		d.Set("grant", grant.GrantOption())
@@ -744,14 +1464,23 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 
 	// Only set privileges if there is a delta in the normalized privileges
 	if grantWithPriv, hasPriv := grant.(MySQLGrantWithPrivileges); hasPriv {
+		privileges := grantWithPriv.GetPrivileges()
+
+		// Column privileges go to column_privileges, unless the configuration still lists them in privileges.
+		if _, isTablePriv := grant.(*TablePrivilegeGrant); isTablePriv && !hasColumnPrivilegeStrings(setToArray(d.Get("privileges"))) {
+			var columnPrivileges map[string][]string
+			privileges, columnPrivileges = splitColumnPrivileges(privileges)
+			d.Set("column_privileges", flattenColumnPrivileges(columnPrivileges, d.Get("column_privileges")))
+		}
+
 		currentPriv, ok := d.GetOk("privileges")
 		if !ok {
-			d.Set("privileges", grantWithPriv.GetPrivileges())
+			d.Set("privileges", privileges)
 		} else {
 			currentPrivs := setToArray(currentPriv.(*schema.Set))
 			currentPrivs = normalizePerms(currentPrivs)
-			if !reflect.DeepEqual(currentPrivs, grantWithPriv.GetPrivileges()) {
-				d.Set("privileges", grantWithPriv.GetPrivileges())
+			if !reflect.DeepEqual(currentPrivs, privileges) {
+				d.Set("privileges", privileges)
 			}
 		}
 	}
@@ -766,7 +1495,7 @@ func setDataFromGrant(grant MySQLGrant, d *schema.ResourceData) *schema.Resource
 	// from the grant itself. We can only infer it from the schema.
 	userOrRole := grant.GetUserOrRole()
 	if d.Get("role") != "" {
-		d.Set("role", userOrRole.Name)
+		d.Set("role", formatRoleReference(userOrRole))
 	} else {
 		d.Set("user", userOrRole.Name)
 		d.Set("host", userOrRole.Host)
@@ -790,6 +1519,16 @@ func combineGrants(grantA MySQLGrant, grantB MySQLGrant) (MySQLGrant, error) {
 	grantBWithPrivileges, bOk := grantB.(MySQLGrantWithPrivileges)
 	if aOk && bOk {
 		grantAWithPrivileges.AppendPrivileges(grantBWithPrivileges.GetPrivileges())
+		if tableA, ok := grantA.(*TablePrivilegeGrant); ok {
+			tableB := grantB.(*TablePrivilegeGrant)
+			tableA.RevokedDatabases = append(tableA.RevokedDatabases, tableB.RevokedDatabases...)
+			for database, privileges := range tableB.RevokedPrivileges {
+				if tableA.RevokedPrivileges == nil {
+					tableA.RevokedPrivileges = map[string][]string{}
+				}
+				tableA.RevokedPrivileges[database] = mergePrivileges(tableA.RevokedPrivileges[database], privileges)
+			}
+		}
 		return grantA, nil
 	}
 
@@ -833,166 +1572,36 @@ func getMatchingGrant(ctx context.Context, db *sql.DB, desiredGrant MySQLGrant)
 	return result, nil
 }
 
-var (
-	kUserOrRoleRegex = regexp.MustCompile("['`]?([^'`]+)['`]?(?:@['`]?([^'`]+)['`]?)?")
-)
-
-func parseUserOrRoleFromRow(userOrRoleStr string) (*UserOrRole, error) {
-	userHostMatches := kUserOrRoleRegex.FindStringSubmatch(userOrRoleStr)
-	if len(userHostMatches) == 3 {
-		return &UserOrRole{
-			Name: userHostMatches[1],
-			Host: userHostMatches[2],
-		}, nil
-	} else if len(userHostMatches) == 2 {
-		return &UserOrRole{
-			Name: userHostMatches[1],
-			Host: "%",
-		}, nil
-	} else {
-		return nil, fmt.Errorf("failed to parse user or role portion of grant statement: %s", userOrRoleStr)
+// showUserGrants returns the parsed SHOW GRANTS of the account, cached for the rest of the run.
+// An account that doesn't exist has no grants.
+func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+	grants, err := showAccountGrants(ctx, db, userOrRole)
+	if isNonExistingGrant(err) {
+		return []MySQLGrant{}, nil
 	}
+	return grants, err
 }
 
-var (
-	kDatabaseAndObjectRegex = regexp.MustCompile("['`]?([^'`]+)['`]?\\.['`]?([^'`]+)['`]?")
-)
-
-func parseDatabaseQualifiedObject(objectRef string) (string, string, error) {
-	if matches := kDatabaseAndObjectRegex.FindStringSubmatch(objectRef); len(matches) == 3 {
-		return matches[1], matches[2], nil
-	}
-	return "", "", fmt.Errorf("failed to parse database and table portion of grant statement: %s", objectRef)
+// showAccountGrants is like showUserGrants, but fails with the server error (see isNonExistingGrant)
+// when the account doesn't exist.
+func showAccountGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+	return userGrantsCache.get(ctx, db, userOrRole, func() ([]MySQLGrant, error) {
+		return readUserGrants(ctx, db, userOrRole)
+	})
 }
 
-var (
-	kRequireRegex = regexp.MustCompile(`.*REQUIRE\s+(.*)`)
-
-	kGrantRegex = regexp.MustCompile(`\bGRANT OPTION\b|\bADMIN OPTION\b`)
-
-	procedureGrantRegex = regexp.MustCompile(`GRANT\s+(.+)\s+ON\s+(FUNCTION|PROCEDURE)\s+(.+)\s+TO\s+(.+)`)
-	tableGrantRegex     = regexp.MustCompile(`GRANT\s+(.+)\s+ON\s+(.+)\s+TO\s+(.+)`)
-	roleGrantRegex      = regexp.MustCompile(`GRANT\s+(.+)\s+TO\s+(.+)`)
-)
-
-func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
-
-	// Ignore REVOKE.*
-	if strings.HasPrefix(grantStr, "REVOKE") {
-		log.Printf("[WARN] Partial revokes are not fully supported and lead to unexpected behavior. Consult documentation https://dev.mysql.com/doc/refman/8.0/en/partial-revokes.html on how to disable them for safe and reliable terraform. Relevant partial revoke: %s\n", grantStr)
-		return nil, nil
-	}
-
-	// Parse Require Statement
-	tlsOption := "NONE"
-	if requireMatches := kRequireRegex.FindStringSubmatch(grantStr); len(requireMatches) == 2 {
-		tlsOption = requireMatches[1]
-	}
-
-	if procedureMatches := procedureGrantRegex.FindStringSubmatch(grantStr); len(procedureMatches) == 5 {
-		privsStr := procedureMatches[1]
-		privileges := extractPermTypes(privsStr)
-		privileges = normalizePerms(privileges)
-
-		// After normalizePerms, we may have empty privileges. If so, skip this grant.
-		if len(privileges) == 0 {
-			return nil, nil
-		}
-
-		userOrRole, err := parseUserOrRoleFromRow(procedureMatches[4])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parseUserOrRole for procedure grant: %w", err)
-		}
-
-		database, callable, err := parseDatabaseQualifiedObject(procedureMatches[3])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parseDatabaseQualifiedObject for procedure grant: %w", err)
-		}
-
-		grant := &ProcedurePrivilegeGrant{
-			Database:     database,
-			ObjectT:      ObjectT(procedureMatches[2]),
-			CallableName: callable,
-			Privileges:   privileges,
-			Grant:        kGrantRegex.MatchString(grantStr),
-			UserOrRole:   *userOrRole,
-			TLSOption:    tlsOption,
-		}
-		log.Printf("[DEBUG] Got procedure parsed grant: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
-		return grant, nil
-	} else if tableMatches := tableGrantRegex.FindStringSubmatch(grantStr); len(tableMatches) == 4 {
-		privsStr := tableMatches[1]
-		privileges := extractPermTypes(privsStr)
-		privileges = normalizePerms(privileges)
-
-		// After normalizePerms, we may have empty privileges. If so, skip this grant.
-		if len(privileges) == 0 {
-			return nil, nil
-		}
-
-		userOrRole, err := parseUserOrRoleFromRow(tableMatches[3])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parseUserOrRole for table grant: %w", err)
-		}
-
-		database, table, err := parseDatabaseQualifiedObject(tableMatches[2])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parseDatabaseQualifiedObject for table grant: %w", err)
-		}
-
-		grant := &TablePrivilegeGrant{
-			Database:   database,
-			Table:      table,
-			Privileges: privileges,
-			Grant:      kGrantRegex.MatchString(grantStr),
-			UserOrRole: *userOrRole,
-			TLSOption:  tlsOption,
-		}
-		log.Printf("[DEBUG] Got table parsed grant: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
-		return grant, nil
-	} else if roleMatches := roleGrantRegex.FindStringSubmatch(grantStr); len(roleMatches) == 3 {
-		rolesStart := strings.Split(roleMatches[1], ",")
-		roles := make([]string, len(rolesStart))
-
-		for i, role := range rolesStart {
-			roles[i] = strings.Trim(role, "`@%\" ")
-		}
-
-		userOrRole, err := parseUserOrRoleFromRow(roleMatches[2])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parseUserOrRole for role grant: %w", err)
-		}
-
-		grant := &RoleGrant{
-			Roles:      roles,
-			Grant:      kGrantRegex.MatchString(grantStr),
-			UserOrRole: *userOrRole,
-			TLSOption:  tlsOption,
-		}
-		log.Printf("[DEBUG] Got: %s, parsed grant is %s: %v", grantStr, reflect.TypeOf(grant), grant)
-		return grant, nil
-
-	} else {
-		return nil, fmt.Errorf("failed to parse object portion of grant statement: %s", grantStr)
-	}
-}
-
-func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+func readUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
 	grants := []MySQLGrant{}
 
 	sqlStatement := fmt.Sprintf("SHOW GRANTS FOR %s", userOrRole.SQLString())
 	log.Printf("[DEBUG] SQL to show grants: %s", sqlStatement)
 	rows, err := db.QueryContext(ctx, sqlStatement)
-
-	if isNonExistingGrant(err) {
-		return []MySQLGrant{}, nil
-	}
-
 	if err != nil {
 		return nil, fmt.Errorf("showUserGrants - getting grants failed: %w", err)
 	}
 
 	defer rows.Close()
+	revokes := []*TablePrivilegeGrant{}
 	for rows.Next() {
 		var rawGrant string
 
@@ -1001,6 +1610,15 @@ func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]M
 			return nil, fmt.Errorf("showUserGrants - reading row failed: %w", err)
 		}
 
+		revoke, err := parsePartialRevokeFromRow(rawGrant)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parsePartialRevokeFromRow: %w", err)
+		}
+		if revoke != nil {
+			revokes = append(revokes, revoke)
+			continue
+		}
+
 		parsedGrant, err := parseGrantFromRow(rawGrant)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parseGrantFromRow: %w", err)
@@ -1019,10 +1637,38 @@ func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]M
 		grants = append(grants, parsedGrant)
 
 	}
+	attachPartialRevokes(grants, revokes)
 	log.Printf("[DEBUG] Parsed grants are: %#v", grants)
 	return grants, nil
 }
 
+// attachPartialRevokes records the databases excluded by REVOKE rows, and the privileges revoked on
+// them, on the global grant they restrict.
+func attachPartialRevokes(grants []MySQLGrant, revokes []*TablePrivilegeGrant) {
+	for _, revoke := range revokes {
+		attached := false
+		for _, grant := range grants {
+			tableGrant, ok := grant.(*TablePrivilegeGrant)
+			if !ok || tableGrant.Database != "*" || tableGrant.Table != "*" || !tableGrant.UserOrRole.Equals(revoke.UserOrRole) {
+				continue
+			}
+			if !slices.Contains(tableGrant.RevokedDatabases, revoke.Database) {
+				tableGrant.RevokedDatabases = append(tableGrant.RevokedDatabases, revoke.Database)
+				sort.Strings(tableGrant.RevokedDatabases)
+			}
+			if tableGrant.RevokedPrivileges == nil {
+				tableGrant.RevokedPrivileges = map[string][]string{}
+			}
+			tableGrant.RevokedPrivileges[revoke.Database] = mergePrivileges(tableGrant.RevokedPrivileges[revoke.Database], revoke.Privileges)
+			attached = true
+			break
+		}
+		if !attached {
+			log.Printf("[WARN] Partial revoke of %v on %s for %s has no global grant to restrict", revoke.Privileges, revoke.Database, revoke.UserOrRole.SQLString())
+		}
+	}
+}
+
 func removeUselessPerms(grants []string) []string {
 	ret := []string{}
 	for _, grant := range grants {
@@ -1033,40 +1679,6 @@ func removeUselessPerms(grants []string) []string {
 	return ret
 }
 
-func extractPermTypes(g string) []string {
-	grants := []string{}
-
-	inParentheses := false
-	currentWord := []rune{}
-	for _, b := range g {
-		switch b {
-		case ',':
-			if inParentheses {
-				currentWord = append(currentWord, b)
-			} else {
-				grants = append(grants, string(currentWord))
-				currentWord = []rune{}
-			}
-			break
-		case '(':
-			inParentheses = true
-			currentWord = append(currentWord, b)
-			break
-		case ')':
-			inParentheses = false
-			currentWord = append(currentWord, b)
-			break
-		default:
-			if unicode.IsSpace(b) && len(currentWord) == 0 {
-				break
-			}
-			currentWord = append(currentWord, b)
-		}
-	}
-	grants = append(grants, string(currentWord))
-	return grants
-}
-
 func normalizeColumnOrder(perm string) string {
 	re := regexp.MustCompile(`^([^(]*)\((.*)\)$`)
 	// We may get inputs like
@@ -1096,8 +1708,139 @@ func normalizeColumnOrder(perm string) string {
 	return fmt.Sprintf("%s(%s)", precursor, partsTogether)
 }
 
+var kReColumnPrivilege = regexp.MustCompile(`^([^(]*)\((.*)\)$`)
+
+func hasColumnPrivilegeStrings(privileges []string) bool {
+	for _, privilege := range privileges {
+		if kReColumnPrivilege.MatchString(privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnPrivilegeString formats a column privilege the way privileges lists it, e.g. SELECT(`a`, `b`).
+func columnPrivilegeString(privilege string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = fmt.Sprintf("`%s`", column)
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(privilege), strings.Join(quoted, ", "))
+}
+
+func columnPrivilegesSQL(columnPrivileges map[string][]string) string {
+	privileges := []string{}
+	for privilege, columns := range columnPrivileges {
+		quoted := make([]string, len(columns))
+		for i, column := range columns {
+			quoted[i] = quoteIdentifier(column)
+		}
+		privileges = append(privileges, fmt.Sprintf("%s(%s)", privilege, strings.Join(quoted, ", ")))
+	}
+	sort.Strings(privileges)
+	return strings.Join(privileges, ", ")
+}
+
+// expandColumnPrivileges reads column_privileges into a map from the uppercased privilege to its columns.
+func expandColumnPrivileges(v interface{}) map[string][]string {
+	result := map[string][]string{}
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return result
+	}
+	for _, elem := range set.List() {
+		m := elem.(map[string]interface{})
+		privilege := strings.ToUpper(m["privilege"].(string))
+		result[privilege] = append(result[privilege], setToArray(m["columns"])...)
+	}
+	for privilege, columns := range result {
+		slices.SortFunc(columns, func(a, b string) int { return strings.Compare(strings.ToLower(a), strings.ToLower(b)) })
+		result[privilege] = slices.CompactFunc(columns, strings.EqualFold)
+	}
+	return result
+}
+
+// flattenColumnPrivileges builds column_privileges from privileges read from the server. Column names keep
+// the spelling of the current state, as SHOW GRANTS returns them uppercased.
+func flattenColumnPrivileges(columnPrivileges map[string][]string, current interface{}) []interface{} {
+	spelling := map[string]string{}
+	for _, columns := range expandColumnPrivileges(current) {
+		for _, column := range columns {
+			spelling[strings.ToUpper(column)] = column
+		}
+	}
+
+	privileges := make([]string, 0, len(columnPrivileges))
+	for privilege := range columnPrivileges {
+		privileges = append(privileges, privilege)
+	}
+	sort.Strings(privileges)
+
+	result := []interface{}{}
+	for _, privilege := range privileges {
+		columns := []interface{}{}
+		for _, column := range columnPrivileges[privilege] {
+			if spelled, ok := spelling[strings.ToUpper(column)]; ok {
+				column = spelled
+			}
+			columns = append(columns, column)
+		}
+		result = append(result, map[string]interface{}{
+			"privilege": privilege,
+			"columns":   columns,
+		})
+	}
+	return result
+}
+
+// splitColumnPrivileges separates privileges like SELECT(`A`, `B`) from the privileges on the whole table.
+func splitColumnPrivileges(privileges []string) ([]string, map[string][]string) {
+	tablePrivileges := []string{}
+	columnPrivileges := map[string][]string{}
+	for _, privilege := range privileges {
+		m := kReColumnPrivilege.FindStringSubmatch(privilege)
+		if m == nil {
+			tablePrivileges = append(tablePrivileges, privilege)
+			continue
+		}
+		name := strings.TrimSpace(m[1])
+		for _, column := range strings.Split(m[2], ",") {
+			columnPrivileges[name] = append(columnPrivileges[name], strings.Trim(column, "` "))
+		}
+	}
+	return tablePrivileges, columnPrivileges
+}
+
+// diffColumnPrivileges returns the columns that have to be revoked and granted to get from oldPrivileges
+// to newPrivileges. Column names are compared case insensitively.
+func diffColumnPrivileges(oldPrivileges, newPrivileges map[string][]string) (toRevoke, toGrant map[string][]string) {
+	missing := func(from, in map[string][]string) map[string][]string {
+		result := map[string][]string{}
+		for privilege, columns := range from {
+			for _, column := range columns {
+				if !slices.ContainsFunc(in[privilege], func(other string) bool { return strings.EqualFold(column, other) }) {
+					result[privilege] = append(result[privilege], column)
+				}
+			}
+		}
+		return result
+	}
+	return missing(oldPrivileges, newPrivileges), missing(newPrivileges, oldPrivileges)
+}
+
 var kReAllPrivileges = regexp.MustCompile(`\bALL ?(PRIVILEGES)?\b`)
 
+// mergePrivileges returns the normalized union of both privilege lists.
+func mergePrivileges(a, b []string) []string {
+	merged := []string{}
+	for _, privilege := range normalizePerms(append(slices.Clone(a), b...)) {
+		if !slices.Contains(merged, privilege) {
+			merged = append(merged, privilege)
+		}
+	}
+	return merged
+}
+
 func normalizePerms(perms []string) []string {
 	ret := []string{}
 	for _, perm := range perms {
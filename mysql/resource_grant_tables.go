@@ -0,0 +1,268 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// A grant with table_include or table_exclude is made on every matching table of the database separately,
+// as MySQL has no way to grant on a subset of tables. The tables holding the grant are kept in the computed
+// attribute tables; new matching tables show up as a diff of it and dropped ones are removed on refresh.
+
+// HasTablePatterns returns whether the grant is made on the tables matching TableInclude and TableExclude.
+func (t *TablePrivilegeGrant) HasTablePatterns() bool {
+	return len(t.TableInclude) > 0 || len(t.TableExclude) > 0
+}
+
+// MatchesTable returns whether table matches any include pattern (or there are none) and no exclude pattern.
+func (t *TablePrivilegeGrant) MatchesTable(table string) bool {
+	matchesAny := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool { return globToRegexp(pattern).MatchString(table) })
+	}
+	if len(t.TableInclude) > 0 && !matchesAny(t.TableInclude) {
+		return false
+	}
+	return !matchesAny(t.TableExclude)
+}
+
+// forTable returns the grant on a single table.
+func (t *TablePrivilegeGrant) forTable(table string) *TablePrivilegeGrant {
+	return &TablePrivilegeGrant{
+		Database:   t.Database,
+		Table:      table,
+		Privileges: t.Privileges,
+		Grant:      t.Grant,
+		UserOrRole: t.UserOrRole,
+		TLSOption:  t.TLSOption,
+	}
+}
+
+// globToRegexp converts a pattern where * matches any characters and ? a single character.
+func globToRegexp(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, `.*`)
+	quoted = strings.ReplaceAll(quoted, `\?`, `.`)
+	return regexp.MustCompile("^" + quoted + "$")
+}
+
+func listMatchingTables(ctx context.Context, db *sql.DB, grant *TablePrivilegeGrant) ([]string, error) {
+	stmtSQL := "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ?"
+	log.Printf("[DEBUG] SQL to list tables: %s (%s)", stmtSQL, grant.Database)
+	rows, err := db.QueryContext(ctx, stmtSQL, grant.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing tables of %s: %w", grant.Database, err)
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, fmt.Errorf("failed listing tables of %s: %w", grant.Database, err)
+		}
+		if grant.MatchesTable(table) {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	return tables, rows.Err()
+}
+
+// grantedTables returns the tables matching the patterns that hold all privileges of the grant.
+func grantedTables(ctx context.Context, db *sql.DB, grant *TablePrivilegeGrant) ([]string, error) {
+	allGrants, err := showUserGrants(ctx, db, grant.UserOrRole)
+	if err != nil {
+		return nil, err
+	}
+
+	byTable := map[string]*TablePrivilegeGrant{}
+	for _, dbGrant := range allGrants {
+		tableGrant, ok := dbGrant.(*TablePrivilegeGrant)
+		if !ok || tableGrant.Database != grant.Database || tableGrant.Table == "*" || !grant.MatchesTable(tableGrant.Table) {
+			continue
+		}
+		if existing, ok := byTable[tableGrant.Table]; ok {
+			existing.Privileges = append(existing.Privileges, tableGrant.Privileges...)
+			existing.Grant = existing.Grant || tableGrant.Grant
+			continue
+		}
+		copied := *tableGrant
+		byTable[tableGrant.Table] = &copied
+	}
+
+	tables := []string{}
+	for table, tableGrant := range byTable {
+		privileges := normalizePerms(tableGrant.Privileges)
+		if len(differenceOf(grant.Privileges, privileges)) == 0 && (tableGrant.Grant || !grant.Grant) {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// plannedTables returns the tables resolved during plan, or resolves them now if they weren't known then.
+func plannedTables(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant *TablePrivilegeGrant) ([]string, error) {
+	if plan := d.GetRawPlan(); !plan.IsNull() && plan.GetAttr("tables").IsKnown() {
+		tables := setToArray(d.Get("tables"))
+		sort.Strings(tables)
+		return tables, nil
+	}
+	return listMatchingTables(ctx, db, grant)
+}
+
+func execIgnoringMissingGrant(ctx context.Context, db *sql.DB, stmtSQL string) error {
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		// Tables dropped since the last refresh have no grant to revoke anymore.
+		if isNonExistingGrant(err) {
+			log.Printf("[WARN] Ignoring error of %s: %v", stmtSQL, err)
+			return nil
+		}
+		return fmt.Errorf("error running SQL (%s): %w", stmtSQL, err)
+	}
+	return nil
+}
+
+func createTablePatternGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant *TablePrivilegeGrant) diag.Diagnostics {
+	tables, err := plannedTables(ctx, db, d, grant)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, table := range tables {
+		stmtSQL := grant.forTable(table).SQLGrantStatement()
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return diag.Errorf("Error running SQL (%v): %v", stmtSQL, err)
+		}
+	}
+
+	d.SetId(grant.GetId())
+	d.Set("tables", tables)
+	return nil
+}
+
+func readTablePatternGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant *TablePrivilegeGrant) diag.Diagnostics {
+	granted, err := grantedTables(ctx, db, grant)
+	if err != nil {
+		return diag.Errorf("failed reading grants on tables of %s: %v", grant.Database, err)
+	}
+	// MySQL keeps the grants of dropped tables, so only tables that still exist are kept in state.
+	existing, err := listMatchingTables(ctx, db, grant)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	tables := []string{}
+	for _, table := range granted {
+		if slices.Contains(existing, table) {
+			tables = append(tables, table)
+		}
+	}
+	d.Set("tables", tables)
+	return nil
+}
+
+func updateTablePatternGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant *TablePrivilegeGrant) diag.Diagnostics {
+	newTables, err := plannedTables(ctx, db, d, grant)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	oldTablesIf, _ := d.GetChange("tables")
+	oldTables := setToArray(oldTablesIf)
+	sort.Strings(oldTables)
+
+	oldPrivsIf, newPrivsIf := d.GetChange("privileges")
+	oldGrant := grant.forTable("*")
+	oldGrant.Privileges = normalizePerms(setToArray(oldPrivsIf))
+
+	// Like on a single table, new grants go first and revokes last.
+	statements, rollbacks := []string{}, []string{}
+	for _, table := range newTables {
+		if !slices.Contains(oldTables, table) {
+			statements = append(statements, grant.forTable(table).SQLGrantStatement())
+			rollbacks = append(rollbacks, grant.forTable(table).SQLRevokeStatement())
+			continue
+		}
+		tableStatements, tableRollbacks, err := privilegeUpdateStatements(grant.forTable(table), setToArray(oldPrivsIf), setToArray(newPrivsIf))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		statements = append(statements, tableStatements...)
+		rollbacks = append(rollbacks, tableRollbacks...)
+	}
+	for _, table := range oldTables {
+		if !slices.Contains(newTables, table) {
+			statements = append(statements, oldGrant.forTable(table).SQLRevokeStatement())
+			rollbacks = append(rollbacks, oldGrant.forTable(table).SQLGrantStatement())
+		}
+	}
+
+	if err := execWithRollback(ctx, db, statements, rollbacks); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("tables", newTables)
+	return nil
+}
+
+func deleteTablePatternGrant(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant *TablePrivilegeGrant) diag.Diagnostics {
+	tables := setToArray(d.Get("tables"))
+	sort.Strings(tables)
+	for _, table := range tables {
+		if err := execIgnoringMissingGrant(ctx, db, grant.forTable(table).SQLRevokeStatement()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+// resolveGrantTables plans the tables a grant with table patterns will be made on, so new matching
+// tables show up as a diff.
+func resolveGrantTables(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	oldInclude, newInclude := d.GetChange("table_include")
+	oldExclude, newExclude := d.GetChange("table_exclude")
+	hadPatterns := oldInclude.(*schema.Set).Len() > 0 || oldExclude.(*schema.Set).Len() > 0
+	hasPatterns := newInclude.(*schema.Set).Len() > 0 || newExclude.(*schema.Set).Len() > 0
+	if d.Id() != "" && hadPatterns != hasPatterns {
+		// Switching between a grant on the database and grants on its tables can't be done in place.
+		key := "table_include"
+		if !d.HasChange(key) {
+			key = "table_exclude"
+		}
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+	}
+	if !hasPatterns {
+		return nil
+	}
+
+	if !d.NewValueKnown("database") || !d.NewValueKnown("table_include") || !d.NewValueKnown("table_exclude") {
+		return d.SetNewComputed("tables")
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+	grant := &TablePrivilegeGrant{
+		Database:     d.Get("database").(string),
+		TableInclude: setToArray(newInclude),
+		TableExclude: setToArray(newExclude),
+	}
+	tables, err := listMatchingTables(ctx, db, grant)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("tables", tables)
+}
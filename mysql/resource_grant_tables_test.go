@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGrant_tablePatterns(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	userName := fmt.Sprintf("jdoe-%s", dbName)
+	execSQL := func(stmts ...string) func() {
+		return func() {
+			ctx := context.Background()
+			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, stmt := range stmts {
+				if _, err := db.Exec(stmt); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipTiDB(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigNoGrant(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					prepareTable(dbName, "orders"),
+					prepareTable(dbName, "customers"),
+					prepareTable(dbName, "customers_pii"),
+				),
+			},
+			{
+				Config: testAccGrantConfigTablePatterns(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "tables.#", "2"),
+					resource.TestCheckTypeSetElemAttr("mysql_grant.test", "tables.*", "orders"),
+					resource.TestCheckTypeSetElemAttr("mysql_grant.test", "tables.*", "customers"),
+					testAccCheckTableGrant(userName, dbName, "customers_pii", false),
+				),
+			},
+			{
+				// A new matching table shows up as drift.
+				PreConfig:          execSQL(fmt.Sprintf("CREATE TABLE `%s`.`payments` (id INT)", dbName)),
+				Config:             testAccGrantConfigTablePatterns(dbName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccGrantConfigTablePatterns(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "tables.#", "3"),
+					testAccCheckTableGrant(userName, dbName, "payments", true),
+				),
+			},
+			{
+				// A dropped table is removed from state without a diff.
+				PreConfig: execSQL(fmt.Sprintf("DROP TABLE `%s`.`payments`", dbName)),
+				Config:    testAccGrantConfigTablePatterns(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "tables.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckTableGrant(userName, dbName, table string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		grant := &TablePrivilegeGrant{
+			Database:     dbName,
+			Table:        "*",
+			Privileges:   []string{"SELECT"},
+			UserOrRole:   UserOrRole{Name: userName, Host: "example.com"},
+			TableInclude: []string{table},
+		}
+		tables, err := grantedTables(ctx, db, grant)
+		if err != nil {
+			return err
+		}
+		if found := len(tables) == 1; found != expected {
+			return fmt.Errorf("expected grant on %s.%s to exist: %t, but it exists: %t", dbName, table, expected, found)
+		}
+		return nil
+	}
+}
+
+func testAccGrantConfigTablePatterns(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+}
+
+resource "mysql_user" "test_global" {
+  user     = "jdoe-%s"
+  host     = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user          = mysql_user.test.user
+  host          = mysql_user.test.host
+  database      = mysql_database.test.name
+  privileges    = ["SELECT"]
+  table_exclude = ["*_pii"]
+}
+`, dbName, dbName, dbName)
+}
+
+func TestTablePatterns(t *testing.T) {
+	grant := &TablePrivilegeGrant{Database: "analytics", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"},
+		TableInclude: []string{"events_*", "daily?"}, TableExclude: []string{"*_pii"}}
+	for table, expected := range map[string]bool{
+		"events_2024":     true,
+		"events_2024_pii": false,
+		"daily1":          true,
+		"daily12":         false,
+		"orders":          false,
+		"events.x":        false,
+	} {
+		if matches := grant.MatchesTable(table); matches != expected {
+			t.Errorf("MatchesTable(%q) = %t, expected %t", table, matches, expected)
+		}
+	}
+
+	excludeOnly := &TablePrivilegeGrant{TableExclude: []string{"*_pii"}}
+	if !excludeOnly.MatchesTable("orders") || excludeOnly.MatchesTable("users_pii") {
+		t.Errorf("expected exclude patterns alone to match every other table")
+	}
+
+	single := grant.forTable("events_2024")
+	if stmt := single.SQLGrantStatement(); stmt != "GRANT SELECT ON `analytics`.`events_2024` TO 'jdoe'@'%'" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+	if single.HasTablePatterns() {
+		t.Errorf("expected the grant on a single table to have no patterns")
+	}
+}
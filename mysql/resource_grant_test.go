@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -370,6 +372,387 @@ func TestAccGrant_role(t *testing.T) {
 	})
 }
 
+func TestAccGrant_roleWithHost(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	roleName := fmt.Sprintf("TFRole-host%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigRoleWithHost(dbName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "role", roleName+"@10.%"),
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+				),
+			},
+			{
+				// The host of the role is read back, so there is no replacement.
+				Config:   testAccGrantConfigRoleWithHost(dbName, roleName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccGrantConfigRoleWithHost(dbName string, roleName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_role" "test" {
+  name = "%s"
+  host = "10.%%"
+}
+
+resource "mysql_grant" "test" {
+  role       = "${mysql_role.test.name}@${mysql_role.test.host}"
+  database   = mysql_database.test.name
+  privileges = ["SELECT"]
+}
+`, dbName, roleName)
+}
+
+func TestAccGrant_revokedDatabases(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	userName := fmt.Sprintf("jdoe-%s", dbName)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.16")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGrantConfigRevokedDatabases(dbName, `"SELECT"`, fmt.Sprintf(`"%s"`, dbName)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "revoked_databases.#", "1"),
+					resource.TestCheckTypeSetElemAttr("mysql_grant.test", "revoked_databases.*", dbName),
+					testAccCheckPartialRevoke(userName, dbName, true),
+				),
+			},
+			{
+				// Granting another privilege globally must not lift the partial revoke.
+				Config: testAccGrantConfigRevokedDatabases(dbName, `"SELECT", "INSERT"`, fmt.Sprintf(`"%s"`, dbName)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "INSERT", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "revoked_databases.#", "1"),
+					testAccCheckPartialRevoke(userName, dbName, true),
+				),
+			},
+			{
+				// Granting one of the privileges on the revoked database again shows up as drift.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("GRANT INSERT ON `%s`.* TO '%s'@'%%'", dbName, userName)); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccGrantConfigRevokedDatabases(dbName, `"SELECT", "INSERT"`, fmt.Sprintf(`"%s"`, dbName)),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccGrantConfigRevokedDatabases(dbName, `"SELECT", "INSERT"`, fmt.Sprintf(`"%s"`, dbName)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "revoked_databases.#", "1"),
+					testAccCheckPartialRevoke(userName, dbName, true),
+				),
+			},
+			{
+				Config: testAccGrantConfigRevokedDatabases(dbName, `"SELECT", "INSERT"`, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "revoked_databases.#", "0"),
+					testAccCheckPartialRevoke(userName, dbName, false),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckPartialRevoke(userName, dbName string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("SHOW GRANTS FOR '%s'@'%%'", userName))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		found := false
+		for rows.Next() {
+			var row string
+			if err := rows.Scan(&row); err != nil {
+				return err
+			}
+			revoke, err := parsePartialRevokeFromRow(row)
+			if err != nil {
+				return err
+			}
+			if revoke != nil && revoke.Database == dbName {
+				found = true
+			}
+		}
+		if found != expected {
+			return fmt.Errorf("expected partial revoke on %s to exist: %t, but it exists: %t", dbName, expected, found)
+		}
+		return rows.Err()
+	}
+}
+
+func testAccGrantConfigRevokedDatabases(dbName, privileges, revokedDatabases string) string {
+	return fmt.Sprintf(`
+resource "mysql_global_variable" "partial_revokes" {
+  name  = "partial_revokes"
+  value = "ON"
+}
+
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-%s"
+  host = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user              = mysql_user.test.user
+  host              = mysql_user.test.host
+  database          = "*"
+  privileges        = [%s]
+  revoked_databases = [%s]
+
+  depends_on = [mysql_global_variable.partial_revokes, mysql_database.test]
+}
+`, dbName, dbName, privileges, revokedDatabases)
+}
+
+func TestAccGrant_columnPrivileges(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheckSkipTiDB(t); testAccPreCheckSkipRds(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Create table first
+				Config: testAccGrantConfigNoGrant(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					prepareTable(dbName, "tbl"),
+				),
+			},
+			{
+				Config: testAccGrantConfigColumnPrivileges(dbName, `"c1", "c2"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT (c1,c2)", true, false),
+					testAccPrivilege("mysql_grant.test", "INSERT (c3)", true, false),
+					resource.TestCheckResourceAttr("mysql_grant.test", "privileges.#", "1"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "column_privileges.#", "2"),
+				),
+			},
+			{
+				Config: testAccGrantConfigColumnPrivileges(dbName, `"c1", "C4"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPrivilege("mysql_grant.test", "SELECT (c1,c4)", true, false),
+					testAccPrivilege("mysql_grant.test", "SELECT (c1,c2)", false, false),
+					testAccPrivilege("mysql_grant.test", "INSERT (c3)", true, false),
+				),
+			},
+			{
+				Config:      testAccGrantConfigColumnPrivileges(dbName, `"c1", "missing"`),
+				ExpectError: regexp.MustCompile("don't exist"),
+			},
+			{
+				Config:            testAccGrantConfigColumnPrivileges(dbName, `"c1", "C4"`),
+				ResourceName:      "mysql_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imported column names are uppercase, as returned by SHOW GRANTS.
+				ImportStateVerifyIgnore: []string{"column_privileges"},
+				ImportStateId:           fmt.Sprintf("%v@%v@%v@%v@", fmt.Sprintf("jdoe-%s", dbName), "example.com", dbName, "tbl"),
+			},
+		},
+	})
+}
+
+func TestAccGrant_invalidPrivileges(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t); testAccPreCheckSkipTiDB(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGrantConfigWithPrivs(dbName, `"SELCT"`, false),
+				ExpectError: regexp.MustCompile(`"SELCT" is not a privilege known to the server`),
+			},
+			{
+				Config:      testAccGrantConfigWithPrivs(dbName, `"SELECT", "RELOAD"`, false),
+				ExpectError: regexp.MustCompile("RELOAD can't be granted on table level"),
+			},
+		},
+	})
+}
+
+func testAccGrantConfigColumnPrivileges(dbName, selectColumns string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user     = "jdoe-%s"
+  host     = "example.com"
+}
+
+resource "mysql_user" "test_global" {
+  user     = "jdoe-%s"
+  host     = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user       = "${mysql_user.test.user}"
+  host       = "${mysql_user.test.host}"
+  table      = "tbl"
+  database   = "${mysql_database.test.name}"
+  privileges = ["DROP"]
+
+  column_privileges {
+    privilege = "SELECT"
+    columns   = [%s]
+  }
+
+  column_privileges {
+    privilege = "insert"
+    columns   = ["c3"]
+  }
+}
+`, dbName, dbName, dbName, selectColumns)
+}
+
+func TestColumnPrivilegeStatements(t *testing.T) {
+	grant := &TablePrivilegeGrant{Database: "app", Table: "t", Grant: true, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	oldPrivileges := map[string][]string{"SELECT": {"a", "b"}, "INSERT": {"c"}}
+	newPrivileges := map[string][]string{"SELECT": {"A", "d"}, "INSERT": {"c"}}
+
+	toRevoke, toGrant := diffColumnPrivileges(oldPrivileges, newPrivileges)
+	if stmt := grant.SQLRevokeColumnsStatement(toRevoke); stmt != "REVOKE SELECT(`b`) ON `app`.`t` FROM 'jdoe'@'%'" {
+		t.Errorf("unexpected revoke statement %s", stmt)
+	}
+	if stmt := grant.SQLGrantColumnsStatement(toGrant); stmt != "GRANT SELECT(`d`) ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION" {
+		t.Errorf("unexpected grant statement %s", stmt)
+	}
+
+	statements, rollbacks := columnPrivilegeUpdateStatements(grant, oldPrivileges, newPrivileges)
+	expectedStatements := []string{
+		"GRANT SELECT(`d`) ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+		"REVOKE SELECT(`b`) ON `app`.`t` FROM 'jdoe'@'%'",
+	}
+	expectedRollbacks := []string{
+		"REVOKE SELECT(`d`) ON `app`.`t` FROM 'jdoe'@'%'",
+		"GRANT SELECT(`b`) ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+	}
+	if !reflect.DeepEqual(statements, expectedStatements) || !reflect.DeepEqual(rollbacks, expectedRollbacks) {
+		t.Errorf("unexpected statements %v with rollbacks %v", statements, rollbacks)
+	}
+
+	privileges, columns := splitColumnPrivileges([]string{"DROP", "INSERT(`C`)", "SELECT(`A`, `D`)"})
+	if !reflect.DeepEqual(privileges, []string{"DROP"}) || !reflect.DeepEqual(columns, map[string][]string{"INSERT": {"C"}, "SELECT": {"A", "D"}}) {
+		t.Errorf("unexpected split %v, %v", privileges, columns)
+	}
+
+	current := schema.NewSet(hashColumnPrivilege, []interface{}{
+		map[string]interface{}{"privilege": "select", "columns": schema.NewSet(hashColumnName, []interface{}{"a", "d"})},
+	})
+	flattened := flattenColumnPrivileges(columns, current)
+	expected := []interface{}{
+		map[string]interface{}{"privilege": "INSERT", "columns": []interface{}{"C"}},
+		map[string]interface{}{"privilege": "SELECT", "columns": []interface{}{"a", "d"}},
+	}
+	if !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("flattened %v, expected %v", flattened, expected)
+	}
+}
+
+func TestPrivilegeUpdateStatements(t *testing.T) {
+	grant := &TablePrivilegeGrant{Database: "app", Table: "t", Grant: true, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	for _, tc := range []struct {
+		oldPrivileges []string
+		newPrivileges []string
+		statements    []string
+		rollbacks     []string
+	}{
+		{
+			[]string{"SELECT", "INSERT", "SELECT (a, b)"},
+			[]string{"select", "UPDATE", "SELECT(a)"},
+			[]string{
+				"GRANT UPDATE ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+				"REVOKE INSERT, SELECT(`B`) ON `app`.`t` FROM 'jdoe'@'%'",
+			},
+			[]string{
+				"REVOKE UPDATE ON `app`.`t` FROM 'jdoe'@'%'",
+				"GRANT INSERT, SELECT(`B`) ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+			},
+		},
+		{
+			[]string{"SELECT", "INSERT"},
+			[]string{"ALL"},
+			[]string{"GRANT ALL PRIVILEGES ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION"},
+			[]string{"REVOKE ALL PRIVILEGES ON `app`.`t` FROM 'jdoe'@'%'"},
+		},
+		{
+			[]string{"ALL PRIVILEGES"},
+			[]string{"SELECT"},
+			[]string{
+				"REVOKE ALL PRIVILEGES ON `app`.`t` FROM 'jdoe'@'%'",
+				"GRANT SELECT ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+			},
+			[]string{
+				"GRANT ALL PRIVILEGES ON `app`.`t` TO 'jdoe'@'%' WITH GRANT OPTION",
+				"REVOKE SELECT ON `app`.`t` FROM 'jdoe'@'%'",
+			},
+		},
+		{
+			[]string{"SELECT"},
+			[]string{"select"},
+			nil,
+			nil,
+		},
+	} {
+		statements, rollbacks, err := privilegeUpdateStatements(grant, tc.oldPrivileges, tc.newPrivileges)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(statements, tc.statements) || !reflect.DeepEqual(rollbacks, tc.rollbacks) {
+			t.Errorf("updating %v to %v: got %q and rollbacks %q, expected %q and %q",
+				tc.oldPrivileges, tc.newPrivileges, statements, rollbacks, tc.statements, tc.rollbacks)
+		}
+	}
+}
+
 func TestAccGrant_roleToUser(t *testing.T) {
 	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
 	roleName := fmt.Sprintf("TFRole-%d", rand.Intn(100))
@@ -518,20 +901,7 @@ func testAccPrivilege(rn string, privilege string, expectExists bool, expectGran
 			return err
 		}
 
-		id := strings.Split(rs.Primary.ID, ":")
-
-		var userOrRole UserOrRole
-		if strings.Contains(id[0], "@") {
-			parts := strings.Split(id[0], "@")
-			userOrRole = UserOrRole{
-				Name: parts[0],
-				Host: parts[1],
-			}
-		} else {
-			userOrRole = UserOrRole{
-				Name: id[0],
-			}
-		}
+		userOrRole := grantAccountFromId(rs.Primary.ID)
 
 		grants, err := showUserGrants(context.Background(), db, userOrRole)
 		if err != nil {
@@ -574,6 +944,15 @@ func testAccPrivilege(rn string, privilege string, expectExists bool, expectGran
 	}
 }
 
+// grantAccountFromId returns the user or role of a grant ID built by GetId.
+func grantAccountFromId(id string) UserOrRole {
+	account := strings.TrimSuffix(splitId(id, ':')[0], ";r")
+	if user, host, ok := parseUserHostId(account); ok {
+		return UserOrRole{Name: user, Host: host}
+	}
+	return UserOrRole{Name: unescapeIdPart(account)}
+}
+
 func testAccGrantCheckDestroy(s *terraform.State) error {
 	ctx := context.Background()
 	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
@@ -586,15 +965,7 @@ func testAccGrantCheckDestroy(s *terraform.State) error {
 			continue
 		}
 
-		id := strings.Split(rs.Primary.ID, ":")
-
-		var userOrRole string
-		if strings.Contains(id[0], "@") {
-			parts := strings.Split(id[0], "@")
-			userOrRole = fmt.Sprintf("'%s'@'%s'", parts[0], parts[1])
-		} else {
-			userOrRole = fmt.Sprintf("'%s'", id[0])
-		}
+		userOrRole := grantAccountFromId(rs.Primary.ID).SQLString()
 
 		stmtSQL := fmt.Sprintf("SHOW GRANTS FOR %s", userOrRole)
 		log.Printf("[DEBUG] SQL: %s", stmtSQL)
@@ -1017,6 +1388,12 @@ func TestAccGrantOnProcedure(t *testing.T) {
 					resource.TestCheckResourceAttr("mysql_grant.test_procedure", "table", "*"),
 				),
 			},
+			{
+				ResourceName:      "mysql_grant.test_procedure",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     fmt.Sprintf("%v@%v:PROCEDURE %v.%v", userName, hostName, dbName, procedureName),
+			},
 		},
 	})
 }
@@ -1381,3 +1758,38 @@ func getAdditionalGrantSample(dbName string, privileges string) string {
     }
     `, dbName, privileges)
 }
+
+func TestValidateGrantAttributes(t *testing.T) {
+	columns := []interface{}{map[string]interface{}{"privilege": "SELECT", "columns": []interface{}{"a"}}}
+	for _, tc := range []struct {
+		name   string
+		raw    map[string]interface{}
+		errMsg string
+	}{
+		{"valid", map[string]interface{}{"database": "app", "privileges": []interface{}{"SELECT"}}, ""},
+		{"revoked databases on a database", map[string]interface{}{"database": "app", "revoked_databases": []interface{}{"payroll"}}, "revoked_databases can only be used"},
+		{"columns on all tables", map[string]interface{}{"database": "app", "column_privileges": columns}, "column_privileges can only be used with a table"},
+		{"columns on a procedure", map[string]interface{}{"database": "PROCEDURE app.p", "column_privileges": columns}, "can't be used with PROCEDURE grants"},
+		{"columns in both", map[string]interface{}{"database": "app", "table": "t", "privileges": []interface{}{"INSERT(b)"}, "column_privileges": columns}, "either in privileges or in column_privileges"},
+		{"table patterns on all databases", map[string]interface{}{"table_include": []interface{}{"t%"}}, "table_include and table_exclude"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.raw["user"] = "jdoe"
+			d := schema.TestResourceDataRaw(t, resourceGrant().Schema, tc.raw)
+			err := validateGrantAttributes(d)
+			grant, diags := parseResourceFromData(d)
+			if tc.errMsg == "" {
+				if err != nil || diags.HasError() || grant == nil {
+					t.Errorf("unexpected error %v, %v", err, diags)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("expected error %q, got %v", tc.errMsg, err)
+			}
+			if !diags.HasError() || grant != nil {
+				t.Errorf("expected parseResourceFromData to fail, got %v, %v", grant, diags)
+			}
+		})
+	}
+}
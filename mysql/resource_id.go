@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Resource IDs join names with separators: @ between user and host, : between the parts of a grant
+// and # in TiDB config IDs. Names and hosts may contain those characters too, so every part is escaped
+// with a backslash. Parts without special characters look the same as before escaping was introduced.
+var idEscaper = strings.NewReplacer(`\`, `\\`, `@`, `\@`, `:`, `\:`, `#`, `\#`)
+
+func escapeIdPart(part string) string {
+	return idEscaper.Replace(part)
+}
+
+func unescapeIdPart(part string) string {
+	if !strings.Contains(part, `\`) {
+		return part
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range part {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// splitId splits id on every unescaped sep. The parts are returned still escaped, so they can be split further.
+func splitId(id string, sep rune) []string {
+	var parts []string
+	start := 0
+	escaped := false
+	for i, r := range id {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			parts = append(parts, id[start:i])
+			start = i + len(string(sep))
+		}
+	}
+	return append(parts, id[start:])
+}
+
+// cutId slices id around the first unescaped sep, like strings.Cut.
+func cutId(id string, sep rune) (before, after string, found bool) {
+	parts := splitId(id, sep)
+	if len(parts) == 1 {
+		return id, "", false
+	}
+	return parts[0], id[len(parts[0])+len(string(sep)):], true
+}
+
+func formatUserHostId(user, host string) string {
+	return escapeIdPart(user) + "@" + escapeIdPart(host)
+}
+
+// parseUserHostId parses USER@HOST. A literal @ in the user name has to be escaped as \@,
+// while the host is everything after the first unescaped @.
+func parseUserHostId(id string) (user, host string, ok bool) {
+	user, host, ok = cutId(id, '@')
+	return unescapeIdPart(user), unescapeIdPart(host), ok
+}
+
+// formatAccountId formats a user as USER@HOST and a role (without host) as just its name.
+func formatAccountId(userOrRole UserOrRole) string {
+	if userOrRole.Host == "" {
+		return escapeIdPart(userOrRole.Name)
+	}
+	return formatUserHostId(userOrRole.Name, userOrRole.Host)
+}
+
+// idStateUpgraders upgrades state of schema version 0, described by v0, which only differs from
+// version 1 in the format of the ID. v0 has to stay frozen even when the resource gets new attributes.
+func idStateUpgraders(v0 *schema.Resource, upgrade schema.StateUpgradeFunc) []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    v0.CoreConfigSchema().ImpliedType(),
+			Upgrade: upgrade,
+		},
+	}
+}
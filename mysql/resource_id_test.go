@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestIdEscaping(t *testing.T) {
+	for _, tc := range []struct {
+		user, host string
+		id         string
+	}{
+		{"jdoe", "%", "jdoe@%"},
+		{"jdoe@example.com", "10.0.0.%", `jdoe\@example.com@10.0.0.%`},
+		{"svc:reader", "::1", `svc\:reader@\:\:1`},
+		{`back\slash`, "localhost", `back\\slash@localhost`},
+	} {
+		id := formatUserHostId(tc.user, tc.host)
+		if id != tc.id {
+			t.Errorf("formatUserHostId(%q, %q) = %q, expected %q", tc.user, tc.host, id, tc.id)
+		}
+		user, host, ok := parseUserHostId(id)
+		if !ok || user != tc.user || host != tc.host {
+			t.Errorf("parseUserHostId(%q) = %q, %q, %t", id, user, host, ok)
+		}
+	}
+
+	// IDs written before escaping keep working: the host is everything after the first @.
+	if user, host, ok := parseUserHostId("jdoe@user@example.com"); !ok || user != "jdoe" || host != "user@example.com" {
+		t.Errorf("unexpected parse of unescaped ID: %q, %q, %t", user, host, ok)
+	}
+	if _, _, ok := parseUserHostId(`jdoe\@example.com`); ok {
+		t.Errorf("expected ID without unescaped @ to be rejected")
+	}
+}
+
+func TestGrantIds(t *testing.T) {
+	user := UserOrRole{Name: "app@svc", Host: "::1"}
+	for _, tc := range []struct {
+		grant MySQLGrant
+		id    string
+	}{
+		{&TablePrivilegeGrant{Database: "db:1", Table: "*", UserOrRole: user}, `app\@svc@\:\:1:db\:1:*`},
+		{&TablePrivilegeGrant{Database: "db", Table: "", UserOrRole: UserOrRole{Name: "reader"}}, `reader:db:*`},
+		{&ProcedurePrivilegeGrant{Database: "`db`", ObjectT: "PROCEDURE", CallableName: "proc", UserOrRole: user}, `app\@svc@\:\:1:PROCEDURE db:proc`},
+		{&RoleGrant{Roles: []string{"reader"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}, `jdoe@%;r`},
+	} {
+		if id := tc.grant.GetId(); id != tc.id {
+			t.Errorf("GetId() of %#v = %q, expected %q", tc.grant, id, tc.id)
+		}
+	}
+}
+
+func TestStateUpgradeV0(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name     string
+		upgrade  func(context.Context, map[string]interface{}, interface{}) (map[string]interface{}, error)
+		rawState map[string]interface{}
+		id       string
+	}{
+		{
+			name:     "user",
+			upgrade:  resourceUserStateUpgradeV0,
+			rawState: map[string]interface{}{"id": "a@b@c", "user": "a@b", "host": "c"},
+			id:       `a\@b@c`,
+		},
+		{
+			name:     "user password",
+			upgrade:  resourceUserPasswordStateUpgradeV0,
+			rawState: map[string]interface{}{"id": "a@b@c", "user": "a@b", "host": "c"},
+			id:       `a\@b@c`,
+		},
+		{
+			name:    "table grant",
+			upgrade: resourceGrantStateUpgradeV0,
+			rawState: map[string]interface{}{
+				"id": "jdoe@%:`app`:`t`", "user": "jdoe", "host": "%", "role": "", "database": "app", "table": "t",
+				"privileges": []interface{}{"SELECT"}, "grant": false, "tls_option": "NONE",
+			},
+			id: "jdoe@%:app:t",
+		},
+		{
+			name:    "role grant",
+			upgrade: resourceGrantStateUpgradeV0,
+			rawState: map[string]interface{}{
+				"id": "jdoe@%", "user": "jdoe", "host": "%", "database": "*", "table": "*", "roles": []interface{}{"reader"},
+			},
+			id: "jdoe@%;r",
+		},
+		{
+			name:     "ti config",
+			upgrade:  resourceTiConfigVariableStateUpgradeV0,
+			rawState: map[string]interface{}{"id": "tikv#a#127.0.0.1:20160", "type": "tikv", "name": "a", "instance": "127.0.0.1:20160"},
+			id:       `tikv#a#127.0.0.1\:20160`,
+		},
+		{
+			name:     "rds config",
+			upgrade:  resourceRDSConfigStateUpgradeV0,
+			rawState: map[string]interface{}{"id": mysqlRdsConfigId},
+			id:       mysqlRdsConfigId,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			upgraded, err := tc.upgrade(ctx, tc.rawState, nil)
+			if err != nil {
+				t.Fatalf("upgrade failed: %v", err)
+			}
+			if upgraded["id"] != tc.id {
+				t.Errorf("upgraded ID is %q, expected %q", upgraded["id"], tc.id)
+			}
+		})
+	}
+}
+
+func TestParseTiConfigId(t *testing.T) {
+	varType, varName, varInstance, err := parseTiConfigId(formatTiConfigId("pd", "log#level", "pd-0:2379"))
+	if err != nil || varType != "pd" || varName != "log#level" || varInstance != "pd-0:2379" {
+		t.Errorf("unexpected round trip: %q, %q, %q, %v", varType, varName, varInstance, err)
+	}
+	for _, id := range []string{"pd", "tidb#x", "pd##", "pd#a#b#c"} {
+		if _, _, _, err := parseTiConfigId(id); err == nil {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestStateUpgraderTypes(t *testing.T) {
+	for name, r := range map[string]*schema.Resource{
+		"mysql_user":          resourceUser(),
+		"mysql_user_password": resourceUserPassword(),
+		"mysql_grant":         resourceGrant(),
+		"mysql_ti_config":     resourceTiConfigVariable(),
+		"mysql_rds_config":    resourceRDSConfig(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if len(r.StateUpgraders) != 1 || r.StateUpgraders[0].Version != 0 {
+				t.Fatalf("expected a single upgrader from version 0, got %v", r.StateUpgraders)
+			}
+			// Every attribute of version 0 still exists, so the upgraded state can be decoded.
+			current := r.CoreConfigSchema().ImpliedType().AttributeTypes()
+			for attr, attrType := range r.StateUpgraders[0].Type.AttributeTypes() {
+				if !current[attr].Equals(attrType) {
+					t.Errorf("attribute %s of version 0 has type %#v, now %#v", attr, attrType, current[attr])
+				}
+			}
+		})
+	}
+
+	// Attributes added after version 0 are not part of it.
+	if resourceUser().StateUpgraders[0].Type.HasAttribute("random_password") {
+		t.Errorf("the version 0 schema of mysql_user must not change with the resource")
+	}
+}
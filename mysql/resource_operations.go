@@ -0,0 +1,208 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Operations are resources that run a statement when they're created and hold no state on the
+// server. All their arguments force a new resource, so they run again whenever the arguments or
+// triggers change, or when Terraform replaces them through replace_triggered_by. Reading and
+// destroying them does nothing.
+
+const unknownThreadErrCode = 1094
+
+// kFlushOptions are the FLUSH options mysql_flush accepts.
+var kFlushOptions = []string{
+	"BINARY LOGS", "ENGINE LOGS", "ERROR LOGS", "GENERAL LOGS", "HOSTS", "LOGS", "OPTIMIZER_COSTS",
+	"PRIVILEGES", "RELAY LOGS", "SLOW LOGS", "STATUS", "TABLES", "USER_RESOURCES",
+}
+
+func operationResource(run func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics, s map[string]*schema.Schema) *schema.Resource {
+	s["triggers"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		ForceNew: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			if diags := run(ctx, d, meta); diags.HasError() {
+				return diags
+			}
+			d.SetId(id.UniqueId())
+			return nil
+		},
+		ReadContext:   schema.NoopContext,
+		DeleteContext: schema.NoopContext,
+		Schema:        s,
+	}
+}
+
+func operationAccountSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"user": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"host": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  "localhost",
+		},
+	}
+}
+
+func resourceKillUserSessions() *schema.Resource {
+	return operationResource(KillUserSessions, map[string]*schema.Schema{
+		// The process list only has the user name, not the host of the account.
+		"user": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"killed_sessions": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	})
+}
+
+func resourceExpirePassword() *schema.Resource {
+	return operationResource(ExpirePassword, operationAccountSchema())
+}
+
+func resourceDiscardOldPassword() *schema.Resource {
+	return operationResource(DiscardOldPassword, operationAccountSchema())
+}
+
+func resourceFlush() *schema.Resource {
+	return operationResource(Flush, map[string]*schema.Schema{
+		"options": {
+			Type:     schema.TypeList,
+			Required: true,
+			ForceNew: true,
+			MinItems: 1,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(kFlushOptions, true),
+			},
+		},
+		"local": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			ForceNew: true,
+			Default:  false,
+		},
+	})
+}
+
+// KillUserSessions kills all connections of the user except the provider's own one.
+func KillUserSessions(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids, err := userSessions(ctx, db, d.Get("user").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	killed := 0
+	for _, id := range ids {
+		stmtSQL := "KILL ?"
+		log.Println("[DEBUG] Executing statement:", stmtSQL, id)
+		if _, err := db.ExecContext(ctx, stmtSQL, id); err != nil {
+			// The session may have ended in the meantime.
+			if mysqlErrorNumber(err) == unknownThreadErrCode {
+				continue
+			}
+			return diag.Errorf("failed killing session %d: %v", id, err)
+		}
+		killed++
+	}
+	d.Set("killed_sessions", killed)
+	return nil
+}
+
+func userSessions(ctx context.Context, db *sql.DB, user string) ([]int64, error) {
+	rows, err := db.QueryContext(ctx, "SELECT ID FROM information_schema.PROCESSLIST WHERE USER = ? AND ID <> CONNECTION_ID()", user)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing sessions of %s: %w", user, err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed scanning sessions: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ExpirePassword marks the password of the account expired, so it has to be changed on the next login.
+func ExpirePassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := "ALTER USER ?@? PASSWORD EXPIRE"
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)); err != nil {
+		return diag.Errorf("failed expiring password: %v", err)
+	}
+	return nil
+}
+
+// DiscardOldPassword discards the password retained by a dual-password change.
+func DiscardOldPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := discardOldUserPassword(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// Flush runs FLUSH with the options, without writing it to the binary log when local is set.
+func Flush(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// FLUSH PRIVILEGES reloads the grant tables, which may have been changed directly.
+	defer invalidateAllUserGrants(db)
+
+	stmtSQL := flushSQL(d.Get("options").([]interface{}), d.Get("local").(bool))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("failed flushing: %v", err)
+	}
+	return nil
+}
+
+// flushSQL returns the FLUSH statement. The options are validated against kFlushOptions.
+func flushSQL(options []interface{}, local bool) string {
+	stmtSQL := "FLUSH "
+	if local {
+		stmtSQL += "NO_WRITE_TO_BINLOG "
+	}
+	flushOptions := make([]string, len(options))
+	for i, option := range options {
+		flushOptions[i] = strings.ToUpper(option.(string))
+	}
+	return stmtSQL + strings.Join(flushOptions, ", ")
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccOperations_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOperationsConfig("one"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPasswordExpired("jdoe-operations", "%"),
+					resource.TestCheckResourceAttr("mysql_kill_user_sessions.test", "killed_sessions", "0"),
+					resource.TestCheckResourceAttrSet("mysql_flush.test", "id"),
+				),
+			},
+			{
+				// Changing triggers runs the operations again.
+				Config: testAccOperationsConfig("two"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccPasswordExpired("jdoe-operations", "%"),
+					resource.TestCheckResourceAttr("mysql_kill_user_sessions.test", "triggers.run", "two"),
+				),
+			},
+		},
+	})
+}
+
+func testAccOperationsConfig(run string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = "jdoe-operations"
+  host               = "%%"
+  plaintext_password = "password"
+}
+
+resource "mysql_expire_password" "test" {
+  user = mysql_user.test.user
+  host = mysql_user.test.host
+
+  triggers = {
+    run = %[1]q
+  }
+}
+
+resource "mysql_kill_user_sessions" "test" {
+  user = mysql_user.test.user
+
+  triggers = {
+    run = %[1]q
+  }
+}
+
+resource "mysql_flush" "test" {
+  options = ["privileges"]
+  local   = true
+
+  triggers = {
+    run = %[1]q
+  }
+}
+`, run)
+}
+
+func testAccPasswordExpired(user, host string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var expired string
+		if err := db.QueryRow("SELECT password_expired FROM mysql.user WHERE user = ? AND host = ?", user, host).Scan(&expired); err != nil {
+			return err
+		}
+		if expired != "Y" {
+			return fmt.Errorf("password of %s@%s isn't expired", user, host)
+		}
+		return nil
+	}
+}
+
+func TestFlushSQL(t *testing.T) {
+	if stmt := flushSQL([]interface{}{"privileges", "USER_RESOURCES"}, false); stmt != "FLUSH PRIVILEGES, USER_RESOURCES" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+	if stmt := flushSQL([]interface{}{"BINARY LOGS"}, true); stmt != "FLUSH NO_WRITE_TO_BINLOG BINARY LOGS" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+}
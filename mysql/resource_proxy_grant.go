@@ -0,0 +1,208 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceProxyGrant manages GRANT PROXY ON proxied account TO proxy account. Proxy grants are read from
+// mysql.proxies_priv, other grant resources skip the PROXY rows of SHOW GRANTS.
+func resourceProxyGrant() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateProxyGrant,
+		ReadContext:   ReadProxyGrant,
+		DeleteContext: DeleteProxyGrant,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportProxyGrant,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"host": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"proxied_user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"proxied_host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "%",
+			},
+
+			"grant": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+type ProxyGrant struct {
+	Proxied    UserOrRole
+	UserOrRole UserOrRole
+	Grant      bool
+}
+
+func (t *ProxyGrant) GetId() string {
+	return fmt.Sprintf("%s:%s", formatUserHostId(t.UserOrRole.Name, t.UserOrRole.Host), formatUserHostId(t.Proxied.Name, t.Proxied.Host))
+}
+
+// proxyAccountSQL always quotes the host, since proxy grants are often made to the anonymous account
+// with an empty host, which is not the same as the one with the % host:
+//
+//	''@''
+//	''@'%'
+func proxyAccountSQL(u UserOrRole) string {
+	return fmt.Sprintf("'%s'@'%s'", u.Name, u.Host)
+}
+
+func (t *ProxyGrant) SQLGrantStatement() string {
+	stmtSQL := fmt.Sprintf("GRANT PROXY ON %s TO %s", proxyAccountSQL(t.Proxied), proxyAccountSQL(t.UserOrRole))
+	if t.Grant {
+		stmtSQL += " WITH GRANT OPTION"
+	}
+	return stmtSQL
+}
+
+func (t *ProxyGrant) SQLRevokeStatement() string {
+	return fmt.Sprintf("REVOKE PROXY ON %s FROM %s", proxyAccountSQL(t.Proxied), proxyAccountSQL(t.UserOrRole))
+}
+
+func proxyGrantFromData(d *schema.ResourceData) *ProxyGrant {
+	return &ProxyGrant{
+		Proxied:    UserOrRole{Name: d.Get("proxied_user").(string), Host: d.Get("proxied_host").(string)},
+		UserOrRole: UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)},
+		Grant:      d.Get("grant").(bool),
+	}
+}
+
+func CreateProxyGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grant := proxyGrantFromData(d)
+	grantCreateMutex.Lock(grant.UserOrRole.IDString())
+	defer grantCreateMutex.Unlock(grant.UserOrRole.IDString())
+	defer invalidateUserGrants(db, grant.UserOrRole)
+
+	stmtSQL := grant.SQLGrantStatement()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+		return diag.Errorf("error running SQL (%s): %v", stmtSQL, err)
+	}
+
+	d.SetId(grant.GetId())
+	return ReadProxyGrant(ctx, d, meta)
+}
+
+// readProxyGrant returns whether the proxy grant exists and whether it has the grant option.
+func readProxyGrant(ctx context.Context, db *sql.DB, grant *ProxyGrant) (bool, bool, error) {
+	stmtSQL := "SELECT With_grant FROM mysql.proxies_priv WHERE User = ? AND Host = ? AND Proxied_user = ? AND Proxied_host = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var withGrant bool
+	err := db.QueryRowContext(ctx, stmtSQL, grant.UserOrRole.Name, grant.UserOrRole.Host, grant.Proxied.Name, grant.Proxied.Host).Scan(&withGrant)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("failed reading proxy grant: %w", err)
+	}
+	return true, withGrant, nil
+}
+
+func ReadProxyGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grant := proxyGrantFromData(d)
+	exists, withGrant, err := readProxyGrant(ctx, db, grant)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		log.Printf("[WARN] Proxy grant (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("grant", withGrant)
+	return nil
+}
+
+func DeleteProxyGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	grant := proxyGrantFromData(d)
+	grantCreateMutex.Lock(grant.UserOrRole.IDString())
+	defer grantCreateMutex.Unlock(grant.UserOrRole.IDString())
+	defer invalidateUserGrants(db, grant.UserOrRole)
+
+	stmtSQL := grant.SQLRevokeStatement()
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL); err != nil && !isNonExistingGrant(err) {
+		return diag.Errorf("error running SQL (%s): %v", stmtSQL, err)
+	}
+
+	return nil
+}
+
+func ImportProxyGrant(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	proxy, proxied, ok := cutId(id, ':')
+	if !ok {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST:PROXIED_USER@PROXIED_HOST)", id)
+	}
+	user, host, ok := parseUserHostId(proxy)
+	if !ok {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST:PROXIED_USER@PROXIED_HOST)", id)
+	}
+	proxiedUser, proxiedHost, ok := parseUserHostId(proxied)
+	if !ok || proxiedUser == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST:PROXIED_USER@PROXIED_HOST)", id)
+	}
+
+	d.Set("user", user)
+	d.Set("host", host)
+	d.Set("proxied_user", proxiedUser)
+	d.Set("proxied_host", proxiedHost)
+
+	readDiags := ReadProxyGrant(ctx, d, meta)
+	if readDiags.HasError() {
+		return nil, fmt.Errorf("failed reading proxy grant: %v", readDiags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("proxy grant %s does not exist", id)
+	}
+	d.SetId(proxyGrantFromData(d).GetId())
+
+	return []*schema.ResourceData{d}, nil
+}
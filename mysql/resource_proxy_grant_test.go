@@ -0,0 +1,148 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccProxyGrant_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccProxyGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProxyGrantConfig("false"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccProxyGrantExists("mysql_proxy_grant.test", false),
+					resource.TestCheckResourceAttr("mysql_proxy_grant.test", "id", "tf-proxy@%:tf-proxied@%"),
+					// The PROXY row doesn't get in the way of other grants of the user.
+					resource.TestCheckResourceAttr("mysql_grant.test", "privileges.#", "1"),
+				),
+			},
+			{
+				Config: testAccProxyGrantConfig("true"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccProxyGrantExists("mysql_proxy_grant.test", true),
+				),
+			},
+			{
+				ResourceName:      "mysql_proxy_grant.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "tf-proxy@%:tf-proxied@%",
+			},
+		},
+	})
+}
+
+func testAccProxyGrantExists(rn string, expectedGrant bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		grant := &ProxyGrant{
+			Proxied:    UserOrRole{Name: rs.Primary.Attributes["proxied_user"], Host: rs.Primary.Attributes["proxied_host"]},
+			UserOrRole: UserOrRole{Name: rs.Primary.Attributes["user"], Host: rs.Primary.Attributes["host"]},
+		}
+		exists, withGrant, err := readProxyGrant(ctx, db, grant)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("proxy grant %s not found", rs.Primary.ID)
+		}
+		if withGrant != expectedGrant {
+			return fmt.Errorf("expected proxy grant %s to have grant option %t, got %t", rs.Primary.ID, expectedGrant, withGrant)
+		}
+		return nil
+	}
+}
+
+func testAccProxyGrantCheckDestroy(s *terraform.State) error {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mysql_proxy_grant" {
+			continue
+		}
+		grant := &ProxyGrant{
+			Proxied:    UserOrRole{Name: rs.Primary.Attributes["proxied_user"], Host: rs.Primary.Attributes["proxied_host"]},
+			UserOrRole: UserOrRole{Name: rs.Primary.Attributes["user"], Host: rs.Primary.Attributes["host"]},
+		}
+		exists, _, err := readProxyGrant(ctx, db, grant)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("proxy grant %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccProxyGrantConfig(grantOption string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "proxied" {
+  user = "tf-proxied"
+  host = "%%"
+}
+
+resource "mysql_user" "proxy" {
+  user = "tf-proxy"
+  host = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.proxy.user
+  host       = mysql_user.proxy.host
+  database   = "*"
+  privileges = ["PROCESS"]
+}
+
+resource "mysql_proxy_grant" "test" {
+  user         = mysql_user.proxy.user
+  host         = mysql_user.proxy.host
+  proxied_user = mysql_user.proxied.user
+  proxied_host = mysql_user.proxied.host
+  grant        = %s
+}
+`, grantOption)
+}
+
+func TestProxyGrantStatements(t *testing.T) {
+	grant := &ProxyGrant{
+		Proxied:    UserOrRole{Name: "app_rw", Host: "%"},
+		UserOrRole: UserOrRole{Name: "", Host: ""},
+		Grant:      true,
+	}
+	if stmt := grant.SQLGrantStatement(); stmt != "GRANT PROXY ON 'app_rw'@'%' TO ''@'' WITH GRANT OPTION" {
+		t.Errorf("unexpected grant statement %s", stmt)
+	}
+	if stmt := grant.SQLRevokeStatement(); stmt != "REVOKE PROXY ON 'app_rw'@'%' FROM ''@''" {
+		t.Errorf("unexpected revoke statement %s", stmt)
+	}
+	if id := grant.GetId(); id != "@:app_rw@%" {
+		t.Errorf("unexpected id %s", id)
+	}
+}
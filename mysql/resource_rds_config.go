@@ -11,18 +11,27 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-// stable non-empty ID
+// stable non-empty ID used before schema version 1, and still used when the server address is unknown
 const mysqlRdsConfigId = "1223234548"
 
+// rdsConfigId identifies the configuration by the address of the server, as there is one per server.
+func rdsConfigId(meta interface{}) string {
+	if conf, ok := meta.(*MySQLConfiguration); ok && conf != nil && conf.Config != nil && conf.Config.Addr != "" {
+		return escapeIdPart(conf.Config.Addr)
+	}
+	return mysqlRdsConfigId
+}
+
 func resourceRDSConfig() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: CreateRDSConfig,
 		UpdateContext: UpdateRDSConfig,
 		ReadContext:   ReadRDSConfig,
 		DeleteContext: DeleteRDSConfig,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: ImportRDSConfig,
 		},
+		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"binlog_retention_hours": {
 				Type:        schema.TypeInt,
@@ -38,6 +47,27 @@ func resourceRDSConfig() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(resourceRDSConfigV0(), resourceRDSConfigStateUpgradeV0)
+	return r
+}
+
+// resourceRDSConfigV0 is the schema of mysql_rds_config at version 0.
+func resourceRDSConfigV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"binlog_retention_hours":   {Type: schema.TypeInt, Optional: true},
+			"replication_target_delay": {Type: schema.TypeInt, Optional: true},
+		},
+	}
+}
+
+// resourceRDSConfigStateUpgradeV0 replaces the fixed ID with one naming the server. If the provider
+// isn't configured yet, the fixed ID is kept, as it still refers to the single configuration.
+func resourceRDSConfigStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState["id"] == mysqlRdsConfigId {
+		rawState["id"] = rdsConfigId(meta)
+	}
+	return rawState, nil
 }
 
 func CreateRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -55,7 +85,7 @@ func CreateRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface
 		}
 	}
 
-	d.SetId(mysqlRdsConfigId)
+	d.SetId(rdsConfigId(meta))
 
 	return nil
 }
@@ -167,3 +197,25 @@ func RDSConfigSQL(d *schema.ResourceData) []string {
 
 	return result
 }
+
+func ImportRDSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// There is a single RDS configuration per server, so any ID refers to it.
+	if d.Id() == "" {
+		return nil, fmt.Errorf("wrong ID format: ID must not be empty")
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+	isRds, err := serverRds(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed detecting RDS: %w", err)
+	}
+	if !isRds {
+		return nil, fmt.Errorf("mysql_rds_config can only be imported from an Amazon RDS server")
+	}
+
+	d.SetId(rdsConfigId(meta))
+	return []*schema.ResourceData{d}, nil
+}
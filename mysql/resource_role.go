@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -14,6 +16,9 @@ func resourceRole() *schema.Resource {
 		CreateContext: CreateRole,
 		ReadContext:   ReadRole,
 		DeleteContext: DeleteRole,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportRole,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -21,19 +26,86 @@ func resourceRole() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "%",
+			},
 		},
 	}
 }
 
+// Roles are referenced as NAME or NAME@HOST in roles of other resources, where a literal @ in the name
+// has to be escaped as \@. A role without host is the one with host %, which is how MySQL stores roles
+// created without one, so both spellings refer to the same role.
+var roleReferenceEscaper = strings.NewReplacer(`\`, `\\`, `@`, `\@`)
+
+func parseRoleReference(ref string) UserOrRole {
+	name, host, _ := parseUserHostId(ref)
+	return withoutDefaultRoleHost(UserOrRole{Name: name, Host: host})
+}
+
+// formatRoleReference returns the canonical reference of role, leaving out host %.
+func formatRoleReference(role UserOrRole) string {
+	if role.Host == "" || role.Host == "%" {
+		return roleReferenceEscaper.Replace(role.Name)
+	}
+	return roleReferenceEscaper.Replace(role.Name) + "@" + roleReferenceEscaper.Replace(role.Host)
+}
+
+func normalizeRoleReferences(refs []string) []string {
+	result := make([]string, len(refs))
+	for i, ref := range refs {
+		result[i] = formatRoleReference(parseRoleReference(ref))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// rolesSQL formats role references as a list of accounts for GRANT, REVOKE and DEFAULT ROLE.
+func rolesSQL(refs []string) string {
+	accounts := make([]string, len(refs))
+	for i, ref := range refs {
+		accounts[i] = parseRoleReference(ref).SQLString()
+	}
+	return strings.Join(accounts, ", ")
+}
+
+// hashRoleReference hashes role references by their canonical form, so reader and reader@% don't differ.
+func hashRoleReference(v interface{}) int {
+	return schema.HashString(normalizeRoleReference(v))
+}
+
+// normalizeRoleReference is the StateFunc of role references. The configured spelling is stored in
+// its canonical form, as read back from the server, so reader@% doesn't differ from reader.
+func normalizeRoleReference(v interface{}) string {
+	return formatRoleReference(parseRoleReference(v.(string)))
+}
+
+// roleFromData returns the role of mysql_role. Host % is left out, as MariaDB roles have no host
+// and in MySQL it's the host of roles created without one.
+func roleFromData(d *schema.ResourceData) UserOrRole {
+	return withoutDefaultRoleHost(UserOrRole{Name: d.Get("name").(string), Host: d.Get("host").(string)})
+}
+
+func withoutDefaultRoleHost(role UserOrRole) UserOrRole {
+	if role.Host == "%" {
+		role.Host = ""
+	}
+	return role
+}
+
 func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	role := roleFromData(d)
+	defer invalidateUserGrants(db, role)
 
-	roleName := d.Get("name").(string)
-
-	sql := fmt.Sprintf("CREATE ROLE '%s'", roleName)
+	sql := fmt.Sprintf("CREATE ROLE %s", role.SQLString())
 	log.Printf("[DEBUG] SQL: %s", sql)
 
 	_, err = db.ExecContext(ctx, sql)
@@ -41,7 +113,8 @@ func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.Errorf("error creating role: %s", err)
 	}
 
-	d.SetId(roleName)
+	// Roles with host % keep the ID of just the name they had before roles got hosts.
+	d.SetId(formatAccountId(role))
 
 	return nil
 }
@@ -52,7 +125,10 @@ func ReadRole(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		return diag.FromErr(err)
 	}
 
-	sql := fmt.Sprintf("SHOW GRANTS FOR '%s'", d.Id())
+	name, host, _ := parseUserHostId(d.Id())
+	role := withoutDefaultRoleHost(UserOrRole{Name: name, Host: host})
+
+	sql := fmt.Sprintf("SHOW GRANTS FOR %s", role.SQLString())
 	log.Printf("[DEBUG] SQL: %s", sql)
 
 	_, err = db.ExecContext(ctx, sql)
@@ -62,7 +138,12 @@ func ReadRole(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		return nil
 	}
 
-	d.Set("name", d.Id())
+	d.Set("name", role.Name)
+	if role.Host == "" {
+		d.Set("host", "%")
+	} else {
+		d.Set("host", role.Host)
+	}
 
 	return nil
 }
@@ -72,8 +153,10 @@ func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	// Dropping the role also revokes it from every account it was granted to.
+	defer invalidateAllUserGrants(db)
 
-	sql := fmt.Sprintf("DROP ROLE '%s'", d.Get("name").(string))
+	sql := fmt.Sprintf("DROP ROLE %s", roleFromData(d).SQLString())
 	log.Printf("[DEBUG] SQL: %s", sql)
 
 	_, err = db.ExecContext(ctx, sql)
@@ -83,3 +166,21 @@ func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	return nil
 }
+
+func ImportRole(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	role := parseRoleReference(d.Id())
+	if role.Name == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected ROLE or ROLE@HOST)", d.Id())
+	}
+
+	d.SetId(formatAccountId(role))
+	readDiags := ReadRole(ctx, d, meta)
+	if readDiags.HasError() {
+		return nil, fmt.Errorf("failed reading role: %v", readDiags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("role %s does not exist", formatRoleReference(role))
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/go-version"
@@ -45,10 +46,170 @@ func TestAccRole_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", roleName),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     roleName,
+			},
 		},
 	})
 }
 
+func TestAccRole_withHost(t *testing.T) {
+	roleName := "tf-test-role-host"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoleWithHostCheckDestroy(roleName, "10.%"),
+		Steps: []resource.TestStep{
+			{
+				// The role is referenced as NAME@HOST and read back the same way, so there is no diff.
+				Config: testAccRoleConfigWithHost(roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_role.test", "host", "10.%"),
+					resource.TestCheckResourceAttr("mysql_role.test", "id", roleName+"@10.%"),
+					resource.TestCheckTypeSetElemAttr("mysql_grant.test", "roles.*", roleName+"@10.%"),
+					resource.TestCheckTypeSetElemAttr("mysql_default_roles.test", "roles.*", roleName+"@10.%"),
+				),
+			},
+			{
+				ResourceName:      "mysql_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     roleName + "@10.%",
+			},
+		},
+	})
+}
+
+func TestAccRole_defaultHostReference(t *testing.T) {
+	roleName := "tf-test-role-default-host"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoleCheckDestroy(roleName),
+		Steps: []resource.TestStep{
+			{
+				// NAME@% is stored as NAME, as it's read back, so there is no diff nor replacement.
+				Config: fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-role-default-host"
+  host = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user     = mysql_user.test.user
+  host     = mysql_user.test.host
+  database = ""
+  roles    = ["${mysql_role.test.name}@%%"]
+}
+`, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("mysql_grant.test", "roles.*", roleName),
+				),
+			},
+		},
+	})
+}
+
+func testAccRoleWithHostCheckDestroy(roleName, host string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host = ?", roleName, host).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("role %s@%s still exists", roleName, host)
+		}
+		return nil
+	}
+}
+
+func testAccRoleConfigWithHost(roleName string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = "%s"
+  host = "10.%%"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-role-host"
+  host = "%%"
+}
+
+resource "mysql_grant" "test" {
+  user     = mysql_user.test.user
+  host     = mysql_user.test.host
+  database = ""
+  roles    = ["${mysql_role.test.name}@${mysql_role.test.host}"]
+}
+
+resource "mysql_default_roles" "test" {
+  user  = mysql_user.test.user
+  host  = mysql_user.test.host
+  roles = mysql_grant.test.roles
+}
+`, roleName)
+}
+
+func TestRoleReferences(t *testing.T) {
+	for ref, expected := range map[string]UserOrRole{
+		"reader":         {Name: "reader"},
+		"reader@%":       {Name: "reader"},
+		"reader@10.%":    {Name: "reader", Host: "10.%"},
+		`team\@x@10.%`:   {Name: "team@x", Host: "10.%"},
+		`team:read@host`: {Name: "team:read", Host: "host"},
+	} {
+		role := parseRoleReference(ref)
+		if role != expected {
+			t.Errorf("parseRoleReference(%q) = %#v, expected %#v", ref, role, expected)
+		}
+		if formatted := formatRoleReference(role); parseRoleReference(formatted) != role {
+			t.Errorf("formatRoleReference(%#v) = %q doesn't parse back", role, formatted)
+		}
+	}
+
+	if normalized := normalizeRoleReferences([]string{"writer@%", "reader@10.%"}); !reflect.DeepEqual(normalized, []string{"reader@10.%", "writer"}) {
+		t.Errorf("unexpected normalized roles %q", normalized)
+	}
+	if hashRoleReference("reader") != hashRoleReference("reader@%") {
+		t.Errorf("expected reader and reader@%% to have the same hash")
+	}
+	if normalized := normalizeRoleReference("reader@%"); normalized != "reader" {
+		t.Errorf("normalizeRoleReference returned %q, expected reader", normalized)
+	}
+
+	grant := &RoleGrant{Roles: []string{"reader", "writer@10.%"}, UserOrRole: UserOrRole{Name: "jdoe", Host: "%"}}
+	if stmt := grant.SQLGrantStatement(); stmt != "GRANT 'reader', 'writer'@'10.%' TO 'jdoe'@'%'" {
+		t.Errorf("unexpected statement %s", stmt)
+	}
+}
+
 func testAccRoleExists(roleName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ctx := context.Background()
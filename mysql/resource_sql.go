@@ -2,8 +2,10 @@ package mysql
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -13,6 +15,9 @@ func resourceSql() *schema.Resource {
 		CreateContext: CreateSql,
 		ReadContext:   ReadSql,
 		DeleteContext: DeleteSql,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSql,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -39,6 +44,8 @@ func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	// The statements may change grants of any account.
+	defer invalidateAllUserGrants(db)
 	name := d.Get("name").(string)
 	createSql := d.Get("create_sql").(string)
 
@@ -63,6 +70,7 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer invalidateAllUserGrants(db)
 	deleteSql := d.Get("delete_sql").(string)
 
 	log.Println("[DEBUG] Executing SQL:", deleteSql)
@@ -75,3 +83,28 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	d.SetId("")
 	return nil
 }
+
+// ImportSql takes NAME|CREATE_SQL|DELETE_SQL, as there is no way to read the statements back from the server.
+// Nothing is executed on import.
+func ImportSql(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("wrong ID format %s (expected NAME|CREATE_SQL|DELETE_SQL; statements containing | cannot be imported)", d.Id())
+	}
+	name := parts[0]
+	createSql := parts[1]
+	deleteSql := parts[2]
+	if name == "" {
+		return nil, fmt.Errorf("wrong ID format %s: NAME must not be empty", d.Id())
+	}
+	if strings.TrimSpace(createSql) == "" || strings.TrimSpace(deleteSql) == "" {
+		return nil, fmt.Errorf("wrong ID format %s: CREATE_SQL and DELETE_SQL must not be empty", d.Id())
+	}
+
+	d.SetId(name)
+	d.Set("name", name)
+	d.Set("create_sql", createSql)
+	d.Set("delete_sql", deleteSql)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,68 @@
+package mysql
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestImportSql(t *testing.T) {
+	testCases := []struct {
+		name        string
+		id          string
+		expectError string
+		createSql   string
+		deleteSql   string
+	}{
+		{
+			name:      "valid",
+			id:        "event_scheduler|SET GLOBAL event_scheduler = ON|SET GLOBAL event_scheduler = OFF",
+			createSql: "SET GLOBAL event_scheduler = ON",
+			deleteSql: "SET GLOBAL event_scheduler = OFF",
+		},
+		{
+			name:        "missing delete",
+			id:          "event_scheduler|SET GLOBAL event_scheduler = ON",
+			expectError: "expected NAME|CREATE_SQL|DELETE_SQL",
+		},
+		{
+			name:        "pipe in statement",
+			id:          "concat|SELECT 'a' || 'b'|SELECT 1",
+			expectError: "expected NAME|CREATE_SQL|DELETE_SQL",
+		},
+		{
+			name:        "empty name",
+			id:          "|SELECT 1|SELECT 2",
+			expectError: "NAME must not be empty",
+		},
+		{
+			name:        "empty statement",
+			id:          "name| |SELECT 2",
+			expectError: "must not be empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := resourceSql().Data(nil)
+			d.SetId(tc.id)
+
+			res, err := ImportSql(context.Background(), d, nil)
+			if tc.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := res[0].Get("create_sql").(string); got != tc.createSql {
+				t.Errorf("create_sql = %q, want %q", got, tc.createSql)
+			}
+			if got := res[0].Get("delete_sql").(string); got != tc.deleteSql {
+				t.Errorf("delete_sql = %q, want %q", got, tc.deleteSql)
+			}
+		})
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
 	"regexp"
-	"strings"
 
 	"github.com/creasty/defaults"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -18,14 +17,15 @@ import (
 )
 
 func resourceTiConfigVariable() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: CreateOrUpdateConfigVariable,
 		ReadContext:   ReadConfigVariable,
 		UpdateContext: CreateOrUpdateConfigVariable,
 		DeleteContext: DeleteConfigVariable,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: ImportConfigVariable,
 		},
+		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
@@ -56,6 +56,55 @@ func resourceTiConfigVariable() *schema.Resource {
 			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(resourceTiConfigVariableV0(), resourceTiConfigVariableStateUpgradeV0)
+	return r
+}
+
+// resourceTiConfigVariableV0 is the schema of mysql_ti_config at version 0.
+func resourceTiConfigVariableV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":     {Type: schema.TypeString, Required: true},
+			"value":    {Type: schema.TypeString, Required: true},
+			"type":     {Type: schema.TypeString, Required: true},
+			"instance": {Type: schema.TypeString, Optional: true},
+		},
+	}
+}
+
+// resourceTiConfigVariableStateUpgradeV0 rebuilds the ID from type, name and instance, escaping # in them.
+func resourceTiConfigVariableStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	varType, _ := rawState["type"].(string)
+	varName, _ := rawState["name"].(string)
+	varInstance, _ := rawState["instance"].(string)
+	if varType == "" || varName == "" {
+		return rawState, nil
+	}
+	rawState["id"] = formatTiConfigId(varType, varName, varInstance)
+	return rawState, nil
+}
+
+// formatTiConfigId returns <pd|tikv>#<config_variable>, followed by #<instance> for a single instance.
+func formatTiConfigId(varType, varName, varInstance string) string {
+	id := fmt.Sprintf("%s#%s", escapeIdPart(varType), escapeIdPart(varName))
+	if varInstance != "" {
+		id = fmt.Sprintf("%s#%s", id, escapeIdPart(varInstance))
+	}
+	return id
+}
+
+func parseTiConfigId(id string) (varType, varName, varInstance string, err error) {
+	parts := splitId(id, '#')
+	for i, part := range parts {
+		parts[i] = unescapeIdPart(part)
+	}
+	if len(parts) < 2 || len(parts) > 3 || (parts[0] != "pd" && parts[0] != "tikv") || parts[1] == "" || (len(parts) == 3 && parts[2] == "") {
+		return "", "", "", fmt.Errorf("wrong ID format %s (expected <pd|tikv>#<config_variable> or <pd|tikv>#<config_variable>#<instance>)", id)
+	}
+	if len(parts) == 3 {
+		varInstance = parts[2]
+	}
+	return parts[0], parts[1], varInstance, nil
 }
 
 func CreateOrUpdateConfigVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -93,12 +142,7 @@ func CreateOrUpdateConfigVariable(ctx context.Context, d *schema.ResourceData, m
 		return diag.Errorf("error setting value: %s -> %s Error: %s", varName, varValue, warnMessage)
 	}
 
-	newId := fmt.Sprintf("%s#%s", varInstanceType, varName)
-	if varInstance != "" {
-		newId = fmt.Sprintf("%s#%s#%s", varInstanceType, varName, varInstance)
-	}
-
-	d.SetId(newId)
+	d.SetId(formatTiConfigId(varInstanceType, varName, varInstance))
 
 	return nil
 }
@@ -111,18 +155,14 @@ func ReadConfigVariable(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
-	match, _ := regexp.MatchString("^(pd|tikv)#(.*)$", d.Id())
-	if !match {
-		return diag.Errorf("error parsing TiDB component (tikv or pd) type from ID.  \n Acceptable format is <pd|tikv>#<config_variable>#<optional_instance>")
+	splitedResType, splitedResName, splitedResInstance, err := parseTiConfigId(d.Id())
+	if err != nil {
+		return diag.Errorf("error parsing TiDB component (tikv or pd) type from ID: %v", err)
 	}
 
-	indexParts := strings.Split(d.Id(), "#")
-	splitedResType := indexParts[0]
-	splitedResName := indexParts[1]
-
 	configQuery := fmt.Sprintf("SHOW CONFIG WHERE type = '%s' AND name = '%s'", splitedResType, splitedResName)
-	if len(indexParts) > 2 {
-		configQuery = configQuery + fmt.Sprintf(" AND instance = '%s'", indexParts[2])
+	if splitedResInstance != "" {
+		configQuery = configQuery + fmt.Sprintf(" AND instance = '%s'", splitedResInstance)
 	}
 
 	log.Printf("[DEBUG] SQL: %s\n", configQuery)
@@ -135,7 +175,7 @@ func ReadConfigVariable(ctx context.Context, d *schema.ResourceData, meta interf
 
 	d.Set("name", resName)
 	d.Set("type", resType)
-	if len(indexParts) > 2 {
+	if splitedResInstance != "" {
 		d.Set("instance", resInstance)
 	}
 	d.Set("value", resValue)
@@ -181,3 +221,19 @@ func DeleteConfigVariable(ctx context.Context, d *schema.ResourceData, meta inte
 
 	return CreateOrUpdateConfigVariable(ctx, d, meta)
 }
+
+func ImportConfigVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, _, _, err := parseTiConfigId(d.Id()); err != nil {
+		return nil, err
+	}
+
+	readDiags := ReadConfigVariable(ctx, d, meta)
+	if readDiags.HasError() {
+		return nil, fmt.Errorf("failed reading config variable: %v", readDiags)
+	}
+	if d.Get("name").(string) == "" {
+		return nil, fmt.Errorf("config variable %s does not exist", d.Id())
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -2,12 +2,14 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -15,7 +17,7 @@ import (
 )
 
 func resourceUser() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: CreateUser,
 		UpdateContext: UpdateUser,
 		ReadContext:   ReadUser,
@@ -23,18 +25,18 @@ func resourceUser() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: ImportUser,
 		},
+		CustomizeDiff: customizeUserDiff,
+		SchemaVersion: 1,
 
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"host": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 				Default:  "localhost",
 			},
 
@@ -56,9 +58,7 @@ func resourceUser() *schema.Resource {
 			"auth_plugin": {
 				Type:             schema.TypeString,
 				Optional:         true,
-				ForceNew:         true,
 				DiffSuppressFunc: NewEmptyStringSuppressFunc,
-				ConflictsWith:    []string{"password"},
 			},
 
 			"aad_identity": {
@@ -103,10 +103,51 @@ func resourceUser() *schema.Resource {
 				DiffSuppressFunc: SuppressHexStringDiff,
 				ConflictsWith:    []string{"plaintext_password", "password", "auth_string_hashed"},
 			},
+			"authentication_factor": userFactorSchema(),
+			"random_password": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ConflictsWith: []string{
+					"plaintext_password", "password", "auth_string_hashed", "auth_string_hex", "aad_identity", "authentication_factor",
+				},
+			},
+			"random_password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				RequiredWith: []string{"random_password"},
+				ValidateFunc: validation.IntBetween(minRandomPasswordLength, maxRandomPasswordLength),
+			},
+			"generated_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"tls_option": {
-				Type:     schema.TypeString,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "NONE",
+				Deprecated:       "Please use tls_requirements instead",
+				ValidateFunc:     validateTLSOption,
+				DiffSuppressFunc: suppressTLSOptionDiff,
+			},
+			"tls_requirements": tlsRequirementsSchema(),
+
+			"verify_password": {
+				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  "NONE",
+				// There is no password to log in with.
+				ConflictsWith: []string{
+					"auth_string_hashed", "auth_string_hex", "aad_identity", "authentication_factor",
+				},
+			},
+			"password_verified": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
 
 			"retain_old_password": {
@@ -121,6 +162,121 @@ func resourceUser() *schema.Resource {
 			},
 		},
 	}
+	for key, policySchema := range userPolicySchema() {
+		r.Schema[key] = policySchema
+	}
+	for key, attributeSchema := range userAttributesSchema() {
+		r.Schema[key] = attributeSchema
+	}
+	r.StateUpgraders = idStateUpgraders(resourceUserV0(), resourceUserStateUpgradeV0)
+	return r
+}
+
+func customizeUserDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateUserFactors(ctx, d, meta); err != nil {
+		return err
+	}
+	if err := validateTLSRequirements(d); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("auth_plugin") {
+		// AAD users are created with CREATE AADUSER, so they can't be changed from or to other plugins.
+		oldPlugin, newPlugin := d.GetChange("auth_plugin")
+		if newPlugin != "" && (oldPlugin == "aad_auth" || newPlugin == "aad_auth") {
+			if err := d.ForceNew("auth_plugin"); err != nil {
+				return err
+			}
+		} else if kPasswordAuthPlugins[newPlugin.(string)] && len(d.Get("authentication_factor").([]interface{})) == 0 &&
+			!configuresCredential(d.GetRawConfig(), userCredentialKeys) {
+			// ALTER USER ... IDENTIFIED WITH without BY or AS clears the password.
+			return fmt.Errorf("changing auth_plugin to %s needs plaintext_password, password, random_password, auth_string_hashed or auth_string_hex to be set", newPlugin)
+		}
+	}
+	if err := diffUserRandomPassword(d); err != nil {
+		return err
+	}
+	return diffUserPasswordVerified(ctx, d, meta)
+}
+
+// userCredentialKeys are the attributes that set the password or the authentication string.
+var userCredentialKeys = []string{"plaintext_password", "password", "random_password", "auth_string_hashed", "auth_string_hex"}
+
+// configuresCredential tells whether the configuration sets one of keys, a non-empty string or a true
+// bool. The state can't be used, as it has the authentication string read from the server.
+func configuresCredential(config cty.Value, keys []string) bool {
+	if config.IsNull() {
+		return false
+	}
+	for _, key := range keys {
+		value := config.GetAttr(key)
+		if !value.IsKnown() {
+			return true
+		}
+		if value.IsNull() {
+			continue
+		}
+		if value.Type() == cty.Bool {
+			if value.True() {
+				return true
+			}
+		} else if value.AsString() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredString returns the value of a string attribute in the configuration, or an empty
+// string if it isn't set. Unlike d.Get, it ignores the value in the state.
+func configuredString(d interface{ GetRawConfig() cty.Value }, key string) string {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return ""
+	}
+	value := config.GetAttr(key)
+	if value.IsNull() || !value.IsKnown() {
+		return ""
+	}
+	return value.AsString()
+}
+
+// resourceUserV0 is the schema of mysql_user at version 0.
+func resourceUserV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user":               {Type: schema.TypeString, Required: true},
+			"host":               {Type: schema.TypeString, Optional: true},
+			"plaintext_password": {Type: schema.TypeString, Optional: true},
+			"password":           {Type: schema.TypeString, Optional: true},
+			"auth_plugin":        {Type: schema.TypeString, Optional: true},
+			"aad_identity": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":     {Type: schema.TypeString, Optional: true},
+						"identity": {Type: schema.TypeString, Required: true},
+					},
+				},
+			},
+			"auth_string_hashed":   {Type: schema.TypeString, Optional: true},
+			"auth_string_hex":      {Type: schema.TypeString, Optional: true},
+			"tls_option":           {Type: schema.TypeString, Optional: true},
+			"retain_old_password":  {Type: schema.TypeBool, Optional: true},
+			"discard_old_password": {Type: schema.TypeBool, Optional: true},
+		},
+	}
+}
+
+// resourceUserStateUpgradeV0 rebuilds the ID from user and host, escaping @ and : in them.
+func resourceUserStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	user, _ := rawState["user"].(string)
+	host, _ := rawState["host"].(string)
+	if user == "" {
+		return rawState, nil
+	}
+	rawState["id"] = formatUserHostId(user, host)
+	return rawState, nil
 }
 
 func checkRetainCurrentPasswordSupport(ctx context.Context, meta interface{}) error {
@@ -144,6 +300,7 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer invalidateUserGrants(db, UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)})
 
 	var authStm string
 	var auth string
@@ -227,11 +384,27 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		password = d.Get("password").(string)
 	}
 
+	randomPassword := d.Get("random_password").(bool)
+	if randomPassword {
+		if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+			return diag.Errorf("cannot use random_password: %v", err)
+		}
+	}
+
 	if auth == "AWSAuthenticationPlugin" && host == "localhost" {
 		return diag.Errorf("cannot use IAM auth against localhost")
 	}
 
-	if authStm != "" {
+	factors := userFactorsFromList(d.Get("authentication_factor").([]interface{}))
+	factorSecrets := map[interface{}]bool{}
+	if len(factors) > 0 {
+		factorsSQL, factorArgs := userFactorsSQL(factors)
+		stmtSQL += " " + factorsSQL
+		args = append(args, factorArgs...)
+		for _, arg := range factorArgs {
+			factorSecrets[arg] = true
+		}
+	} else if authStm != "" {
 		stmtSQL += authStm
 		if hashed != "" {
 			args = append(args, hashed)
@@ -239,29 +412,35 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		if password != "" {
 			stmtSQL += " BY ?"
 			args = append(args, password)
+		} else if randomPassword {
+			stmtSQL += " BY RANDOM PASSWORD"
 		}
 	} else if password != "" {
 		stmtSQL += " IDENTIFIED BY ?"
 		args = append(args, password)
+	} else if randomPassword {
+		stmtSQL += " IDENTIFIED BY RANDOM PASSWORD"
 	}
 
 	requiredVersion, _ := version.NewVersion("5.7.0")
 	var updateStmtSql string
 	var updateArgs []interface{}
 
-	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) && d.Get("tls_option").(string) != "" {
+	requireSQL, requireArgs := userRequireSQL(d)
+	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) && requireSQL != "" {
 		if createObj == "AADUSER" {
-			updateStmtSql = "ALTER USER ?@? REQUIRE " + d.Get("tls_option").(string)
-			updateArgs = []interface{}{user, host}
+			updateStmtSql = "ALTER USER ?@? REQUIRE " + requireSQL
+			updateArgs = append([]interface{}{user, host}, requireArgs...)
 		} else {
-			stmtSQL += " REQUIRE " + d.Get("tls_option").(string)
+			stmtSQL += " REQUIRE " + requireSQL
+			args = append(args, requireArgs...)
 		}
 	}
 
 	// Redact sensitive values in args for logging
 	redactedArgs := make([]interface{}, len(args))
 	for i, arg := range args {
-		if (password != "" && arg == password) || (hashed != "" && arg == hashed) {
+		if (password != "" && arg == password) || (hashed != "" && arg == hashed) || factorSecrets[arg] {
 			redactedArgs[i] = "<SENSITIVE>"
 		} else {
 			redactedArgs[i] = arg
@@ -270,24 +449,49 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	log.Println("[DEBUG] Executing statement:", stmtSQL, "args:", redactedArgs)
 
-	_, err = db.ExecContext(ctx, stmtSQL, args...)
-	if err != nil {
-		return diag.Errorf("failed executing SQL: %v", err)
+	if randomPassword {
+		generated, err := execRandomPassword(ctx, db, d.Get("random_password_length").(int), stmtSQL, args...)
+		if err != nil {
+			return diag.Errorf("failed executing SQL: %v", err)
+		}
+		d.Set("generated_password", generated)
+	} else {
+		_, err = db.ExecContext(ctx, stmtSQL, args...)
+		if err != nil {
+			return diag.Errorf("failed executing SQL: %v", err)
+		}
 	}
 
-	userId := fmt.Sprintf("%s@%s", user, host)
-	d.SetId(userId)
+	d.SetId(formatUserHostId(user, host))
+	d.Set("password_verified", true)
 
 	if updateStmtSql != "" {
 		log.Println("[DEBUG] Executing statement:", updateStmtSql, "args:", updateArgs)
 		_, err = db.ExecContext(ctx, updateStmtSql, updateArgs...)
 		if err != nil {
 			d.Set("tls_option", "")
+			d.Set("tls_requirements", nil)
 			return diag.Errorf("failed executing SQL: %v", err)
 		}
 	}
 
-	return nil
+	if len(factors) > 0 {
+		challenges, err := registerUserFactors(ctx, db, user, host, userFactorRegistrations(nil, factors))
+		setUserFactorsState(d, challenges)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if err := alterUserPolicy(ctx, db, user, host, configuredUserPolicy(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := alterUserAttributes(ctx, db, user, host, changedUserAttributes(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return authPluginDiagnostics(ctx, meta, user, host, auth)
 }
 
 func getSetPasswordStatement(ctx context.Context, meta interface{}, retainPassword bool) (string, error) {
@@ -309,20 +513,38 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	defer invalidateUserGrants(db, UserOrRole{Name: d.Get("user").(string), Host: d.Get("host").(string)})
+
+	if d.HasChanges("user", "host") {
+		// Grants and default roles follow the account when it's renamed.
+		oldUser, newUser := d.GetChange("user")
+		oldHost, newHost := d.GetChange("host")
+		stmtSQL := "RENAME USER ?@? TO ?@?"
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL, oldUser, oldHost, newUser, newHost); err != nil {
+			return diag.Errorf("failed renaming user: %v", err)
+		}
+		invalidateUserGrants(db, UserOrRole{Name: oldUser.(string), Host: oldHost.(string)})
+		d.SetId(formatUserHostId(newUser.(string), newHost.(string)))
+	}
 
 	var auth string
 	if v, ok := d.GetOk("auth_plugin"); ok {
 		auth = v.(string)
 	}
-	if len(auth) > 0 {
-		if d.HasChange("tls_option") || d.HasChange("auth_plugin") || d.HasChange("auth_string_hashed") || d.HasChange("auth_string_hex") {
-			var stmtSQL string
-
+	passwordSet := false
+	randomPassword := false
+	if len(auth) > 0 && len(d.Get("authentication_factor").([]interface{})) == 0 {
+		if d.HasChange("auth_plugin") || d.HasChange("auth_string_hashed") || d.HasChange("auth_string_hex") {
+			args := []interface{}{d.Get("user").(string), d.Get("host").(string)}
+
+			// The state has the authentication string read from the server, which belongs to the old
+			// plugin, so only the configured one is used.
 			authString := ""
-			if d.Get("auth_string_hashed").(string) != "" {
-				authString = fmt.Sprintf("IDENTIFIED WITH %s AS '%s'", d.Get("auth_plugin"), d.Get("auth_string_hashed"))
-			} else if d.Get("auth_string_hex").(string) != "" {
-				authStringHex := d.Get("auth_string_hex").(string)
+			if hashed := configuredString(d, "auth_string_hashed"); hashed != "" {
+				authString = fmt.Sprintf("IDENTIFIED WITH %s AS ?", d.Get("auth_plugin"))
+				args = append(args, hashed)
+			} else if authStringHex := configuredString(d, "auth_string_hex"); authStringHex != "" {
 				normalizedHex := normalizeHexString(authStringHex)
 
 				hexDigits := normalizedHex[2:]
@@ -330,21 +552,63 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 					return diag.Errorf("invalid hex string for auth_string_hex: %v", err)
 				}
 				authString = fmt.Sprintf("IDENTIFIED WITH %s AS 0x%s", d.Get("auth_plugin"), hexDigits)
+			} else if d.HasChange("auth_plugin") {
+				// Changing the plugin needs the password, which is hashed by the new plugin.
+				authString = fmt.Sprintf("IDENTIFIED WITH %s", auth)
+				if auth == "AWSAuthenticationPlugin" {
+					authString += " AS 'RDS'"
+				} else if password := configuredString(d, "plaintext_password"); password != "" {
+					authString += " BY ?"
+					args = append(args, password)
+					passwordSet = true
+				} else if password := configuredString(d, "password"); password != "" {
+					authString += " BY ?"
+					args = append(args, password)
+					passwordSet = true
+				} else if d.Get("random_password").(bool) {
+					authString += " BY RANDOM PASSWORD"
+					randomPassword = true
+					passwordSet = true
+				}
 			}
-			stmtSQL = fmt.Sprintf("ALTER USER `%s`@`%s` %s  REQUIRE %s",
-				d.Get("user").(string),
-				d.Get("host").(string),
-				authString,
-				d.Get("tls_option").(string))
-
-			log.Println("[DEBUG] Executing query:", stmtSQL)
-			_, err := db.ExecContext(ctx, stmtSQL)
-			if err != nil {
-				return diag.Errorf("failed running query: %v", err)
+			// REQUIRE is left to the tls_option statement below.
+			if randomPassword {
+				if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+					return diag.Errorf("cannot use random_password: %v", err)
+				}
+				generated, err := execRandomPassword(ctx, db, d.Get("random_password_length").(int), "ALTER USER ?@? "+authString, args...)
+				if err != nil {
+					return diag.Errorf("failed generating password: %v", err)
+				}
+				d.Set("generated_password", generated)
+			} else if authString != "" {
+				stmtSQL := "ALTER USER ?@? " + authString
+
+				log.Println("[DEBUG] Executing query:", stmtSQL)
+				_, err := db.ExecContext(ctx, stmtSQL, args...)
+				if err != nil {
+					return diag.Errorf("failed running query: %v", err)
+				}
 			}
 		}
 	}
 
+	if d.HasChange("authentication_factor") {
+		oldList, newList := d.GetChange("authentication_factor")
+		oldFactors := userFactorsFromList(oldList.([]interface{}))
+		newFactors := userFactorsFromList(newList.([]interface{}))
+		user := d.Get("user").(string)
+		host := d.Get("host").(string)
+		if err := alterUserFactors(ctx, db, user, host, userFactorStatements(oldFactors, newFactors)); err != nil {
+			return diag.FromErr(err)
+		}
+		challenges, err := registerUserFactors(ctx, db, user, host, userFactorRegistrations(oldFactors, newFactors))
+		setUserFactorsState(d, challenges)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	discardOldPassword := d.Get("discard_old_password").(bool)
 	if discardOldPassword {
 		err := checkDiscardOldPasswordSupport(ctx, meta)
@@ -369,6 +633,13 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		_, newpw = d.GetChange("plaintext_password")
 	} else if d.HasChange("password") {
 		_, newpw = d.GetChange("password")
+	} else if userPasswordDrifted(d) && !d.Get("random_password").(bool) {
+		// The password was changed outside of Terraform, so the configured one is set again.
+		if password := configuredString(d, "plaintext_password"); password != "" {
+			newpw = password
+		} else if password := configuredString(d, "password"); password != "" {
+			newpw = password
+		}
 	} else {
 		newpw = nil
 	}
@@ -381,7 +652,24 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
-	if newpw != nil {
+	if d.Get("random_password").(bool) {
+		if !randomPassword && (d.HasChanges("random_password", "random_password_length", "keepers") || userPasswordDrifted(d)) {
+			if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+				return diag.Errorf("cannot use random_password: %v", err)
+			}
+			generated, err := execRandomPassword(ctx, db, d.Get("random_password_length").(int), randomPasswordStatement(retainPassword),
+				d.Get("user").(string),
+				d.Get("host").(string))
+			if err != nil {
+				return diag.Errorf("failed generating password: %v", err)
+			}
+			d.Set("generated_password", generated)
+		}
+	} else {
+		d.Set("generated_password", "")
+	}
+
+	if newpw != nil && !passwordSet {
 		stmtSQL, err := getSetPasswordStatement(ctx, meta, retainPassword)
 		if err != nil {
 			return diag.Errorf("failed getting change password statement: %v", err)
@@ -396,23 +684,31 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 			return diag.Errorf("failed changing password: %v", err)
 		}
 	}
+	if userPasswordDrifted(d) {
+		d.Set("password_verified", true)
+	}
 
 	requiredVersion, _ := version.NewVersion("5.7.0")
-	if d.HasChange("tls_option") && getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
-		var stmtSQL string
-
-		stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' REQUIRE %s",
-			d.Get("user").(string),
-			d.Get("host").(string),
-			d.Get("tls_option").(string))
+	if (d.HasChange("tls_option") || d.HasChange("tls_requirements")) && getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
+		requireSQL, requireArgs := userRequireSQL(d)
+		stmtSQL := "ALTER USER ?@? REQUIRE " + requireSQL
+		args := append([]interface{}{d.Get("user").(string), d.Get("host").(string)}, requireArgs...)
 
 		log.Println("[DEBUG] Executing query:", stmtSQL)
-		_, err := db.ExecContext(ctx, stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL, args...)
 		if err != nil {
 			return diag.Errorf("failed setting require tls option: %v", err)
 		}
 	}
 
+	if err := alterUserPolicy(ctx, db, d.Get("user").(string), d.Get("host").(string), changedUserPolicy(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := alterUserAttributes(ctx, db, d.Get("user").(string), d.Get("host").(string), changedUserAttributes(d)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -442,18 +738,33 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 			}
 			return diag.Errorf("failed getting user: %v", err)
 		}
+		if err := setUserAttributes(ctx, db, d); err != nil {
+			return diag.FromErr(err)
+		}
 		// Examples of create user:
 		// CREATE USER 'some_app'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*0something' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK
 		// CREATE USER `jdoe-tf-test-47`@`example.com` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT
 		// CREATE USER `jdoe`@`example.com` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$i`xay#fG/\' TrbkNA82' REQUIRE NONE PASSWORD
 		// CREATE USER `hashed_hex`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS 0x244124303035242522434C16580334755221766C29210D2C415E033550367655494F314864686775414E735A742E6F474857504B623172525066574D524F30506B7A79646F30 REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT
 
-		re := regexp.MustCompile("^CREATE USER ['`]([^'`]*)['`]@['`]([^'`]*)['`] IDENTIFIED WITH ['`]([^'`]*)['`] (?:AS (?:'((?:.*?[^\\\\])?)'|(0x[0-9A-Fa-f]+)) )?REQUIRE ([^ ]*)")
-		if m := re.FindStringSubmatch(createUserStmt); len(m) == 7 {
+		// CREATE USER `mfa`@`%` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$...' AND IDENTIFIED WITH 'authentication_ldap_simple' AS 'uid=mfa' REQUIRE NONE ...
+
+		re := regexp.MustCompile("^CREATE USER ['`]([^'`]*)['`]@['`]([^'`]*)['`] IDENTIFIED WITH ['`]([^'`]*)['`] (?:AS (?:'((?:.*?[^\\\\])?)'|(0x[0-9A-Fa-f]+)) )?((?:AND IDENTIFIED WITH ['`][^'`]*['`] (?:AS (?:'(?:.*?[^\\\\])?'|0x[0-9A-Fa-f]+) )?)*)REQUIRE ([^ ]*)")
+		if loc := re.FindStringSubmatchIndex(createUserStmt); loc != nil {
+			// The TLS requirements and the options follow the authentication, which may contain
+			// anything in its hash.
+			requirements, length := parseTLSRequirements(createUserStmt[loc[14]:])
+			if length == 0 {
+				requirements, length = tlsRequirements{Type: createUserStmt[loc[14]:loc[15]]}, loc[15]-loc[14]
+			}
+			setTLSRequirements(d, requirements)
+			setUserPolicy(d, createUserStmt[loc[14]+length:])
+		}
+		if m := re.FindStringSubmatch(createUserStmt); len(m) == 8 {
 			d.Set("user", m[1])
 			d.Set("host", m[2])
 			d.Set("auth_plugin", m[3])
-			d.Set("tls_option", m[6])
+			setUserFactors(d, append([]userFactor{{Plugin: m[3], AuthString: m[4]}}, parseUserFactors(m[6])...))
 
 			if m[3] == "aad_auth" {
 				// AADGroup:98e61c8d-e104-4f8c-b1a6-7ae873617fe6:upn:Doe_Family_Group
@@ -505,13 +816,15 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 					d.Set("auth_string_hex", "")
 				}
 			}
-			return nil
+			diags := authPluginDiagnostics(ctx, meta, m[1], m[2], m[3])
+			return append(diags, verifyUserStatePassword(ctx, d, meta)...)
 		}
 
 		// Try 2 - just whether the user is there.
 		re2 := regexp.MustCompile("^CREATE USER")
 		if m := re2.FindStringSubmatch(createUserStmt); m != nil {
 			// Ok, we have at least something - it's probably in MariaDB.
+			setUserPolicy(d, createUserStmt)
 			return nil
 		}
 		return diag.Errorf("Create user couldn't be parsed - it is %s", createUserStmt)
@@ -539,11 +852,87 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	return nil
 }
 
+// verifyUserStatePassword checks the password in the state with verify_password and records the result
+// in password_verified. plaintext_password is only in the state as a hash, so it's verified with the
+// configured value when planning instead, see diffUserPasswordVerified.
+func verifyUserStatePassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	verified := true
+	var diags diag.Diagnostics
+	password := d.Get("password").(string)
+	if d.Get("random_password").(bool) {
+		password = d.Get("generated_password").(string)
+	}
+	if d.Get("verify_password").(bool) && password != "" {
+		user, host := d.Get("user").(string), d.Get("host").(string)
+		verified, diags = verifyUserPassword(ctx, meta, user, host, password)
+		if !verified {
+			diags = append(diags, passwordChangedWarning(user, host))
+		}
+	}
+	d.Set("password_verified", verified)
+	return diags
+}
+
+// diffUserPasswordVerified plans to set the password again when verify_password found it changed. It
+// logs in with a configured plaintext_password that is already in the state as its hash.
+func diffUserPasswordVerified(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.Get("verify_password").(bool) || d.HasChanges("user", "host") {
+		return nil
+	}
+	verified := statePasswordVerified(d.GetRawState())
+	if password := configuredString(d, "plaintext_password"); verified && password != "" && !d.HasChange("plaintext_password") {
+		user, host := d.Get("user").(string), d.Get("host").(string)
+		var diags diag.Diagnostics
+		verified, diags = verifyUserPassword(ctx, meta, user, host, password)
+		for _, warning := range diags {
+			log.Printf("[WARN] %s: %s", warning.Summary, warning.Detail)
+		}
+	}
+	if verified {
+		return nil
+	}
+	if d.Get("random_password").(bool) {
+		if err := d.SetNewComputed("generated_password"); err != nil {
+			return err
+		}
+	}
+	return d.SetNewComputed("password_verified")
+}
+
+// statePasswordVerified returns password_verified of the state. State without it, e.g. from before it
+// existed, wasn't found changed.
+func statePasswordVerified(state cty.Value) bool {
+	if state.IsNull() {
+		return true
+	}
+	value := state.GetAttr("password_verified")
+	return !value.IsKnown() || value.IsNull() || value.True()
+}
+
+// userPasswordDrifted tells whether verify_password found the password changed outside of Terraform.
+// When it was found while refreshing, the state has password_verified false; when it was found while
+// planning, the plan has it unknown.
+func userPasswordDrifted(d *schema.ResourceData) bool {
+	return d.Get("verify_password").(bool) && (!statePasswordVerified(d.GetRawState()) || d.HasChange("password_verified"))
+}
+
+// userExists tells whether the account exists.
+func userExists(ctx context.Context, db *sql.DB, user, host string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host = ?", user, host).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed looking up user %s@%s: %w", user, host, err)
+	}
+	return count > 0, nil
+}
+
 func DeleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	// Dropping the user also drops it from the grants of accounts it was granted to as a role.
+	defer invalidateAllUserGrants(db)
 
 	stmtSQL := fmt.Sprintf("DROP USER ?@?")
 
@@ -560,23 +949,22 @@ func DeleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 }
 
 func ImportUser(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	userHost := strings.SplitN(d.Id(), "@", 2)
-
-	if len(userHost) != 2 {
-		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
+	user, host, ok := parseUserHostId(d.Id())
+	if !ok || user == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST, with @ in the user name escaped as \\@)", d.Id())
 	}
 
-	user := userHost[0]
-	host := userHost[1]
 	d.Set("user", user)
 	d.Set("host", host)
 	err := ReadUser(ctx, d, meta)
-	var ferror error
 	if err.HasError() {
-		ferror = fmt.Errorf("failed reading user: %v", err)
+		return nil, fmt.Errorf("failed reading user: %v", err)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("user %s@%s does not exist", user, host)
 	}
 
-	return []*schema.ResourceData{d}, ferror
+	return []*schema.ResourceData{d}, nil
 }
 
 func NewEmptyStringSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
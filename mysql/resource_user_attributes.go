@@ -0,0 +1,155 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// MySQL 8.0.21+ keeps a JSON object of attributes per account, shown in
+// information_schema.USER_ATTRIBUTES. COMMENT 'text' is a shorthand for the "comment" attribute, so
+// comment and attributes are both written with ALTER USER ... ATTRIBUTE, which merge-patches the
+// object: keys set to null are removed and all other keys are left alone.
+
+const unknownTableErrCode = 1109
+
+const userCommentAttribute = "comment"
+
+func userAttributesSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"comment": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"attributes": {
+			Type:             schema.TypeMap,
+			Optional:         true,
+			Elem:             &schema.Schema{Type: schema.TypeString},
+			ValidateDiagFunc: validateUserAttributes,
+		},
+	}
+}
+
+func validateUserAttributes(v interface{}, path cty.Path) diag.Diagnostics {
+	if _, ok := v.(map[string]interface{})[userCommentAttribute]; ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("the %q attribute is set with the comment argument", userCommentAttribute),
+			AttributePath: append(path, cty.IndexStep{Key: cty.StringVal(userCommentAttribute)}),
+		}}
+	}
+	return nil
+}
+
+// splitUserAttributes returns the comment and the other attributes of an ATTRIBUTE JSON object.
+// Values that aren't strings are kept as their JSON encoding.
+func splitUserAttributes(attributeJSON string) (string, map[string]string, error) {
+	attributes := map[string]string{}
+	if attributeJSON == "" {
+		return "", attributes, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(attributeJSON), &raw); err != nil {
+		return "", nil, fmt.Errorf("failed parsing user attributes %s: %w", attributeJSON, err)
+	}
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			s = string(value)
+		}
+		attributes[key] = s
+	}
+
+	comment := attributes[userCommentAttribute]
+	delete(attributes, userCommentAttribute)
+	return comment, attributes, nil
+}
+
+// userAttributesPatch returns the merge patch turning the old comment and attributes into the new
+// ones. It is empty if nothing changed.
+func userAttributesPatch(oldComment, newComment string, oldAttributes, newAttributes map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range newAttributes {
+		if old, ok := oldAttributes[key]; !ok || old != value {
+			patch[key] = value
+		}
+	}
+	for key := range oldAttributes {
+		if _, ok := newAttributes[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	if oldComment != newComment {
+		if newComment == "" {
+			patch[userCommentAttribute] = nil
+		} else {
+			patch[userCommentAttribute] = newComment
+		}
+	}
+	return patch
+}
+
+// readUserAttributes returns the ATTRIBUTE JSON of the user. It returns false if the server doesn't
+// support user attributes.
+func readUserAttributes(ctx context.Context, db *sql.DB, user, host string) (string, bool, error) {
+	stmtSQL := "SELECT ATTRIBUTE FROM information_schema.USER_ATTRIBUTES WHERE USER = ? AND HOST = ?"
+	log.Println("[DEBUG] Executing query:", stmtSQL)
+
+	var attributeJSON sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&attributeJSON)
+	if err != nil {
+		if mysqlErrorNumber(err) == unknownTableErrCode {
+			return "", false, nil
+		}
+		if err == sql.ErrNoRows {
+			return "", true, nil
+		}
+		return "", false, fmt.Errorf("failed reading user attributes: %w", err)
+	}
+	return attributeJSON.String, true, nil
+}
+
+func setUserAttributes(ctx context.Context, db *sql.DB, d *schema.ResourceData) error {
+	attributeJSON, supported, err := readUserAttributes(ctx, db, d.Get("user").(string), d.Get("host").(string))
+	if err != nil || !supported {
+		return err
+	}
+	comment, attributes, err := splitUserAttributes(attributeJSON)
+	if err != nil {
+		return err
+	}
+	d.Set("comment", comment)
+	d.Set("attributes", attributes)
+	return nil
+}
+
+// changedUserAttributes returns the merge patch from the state to the configuration.
+func changedUserAttributes(d *schema.ResourceData) map[string]interface{} {
+	oldComment, newComment := d.GetChange("comment")
+	oldAttributes, newAttributes := d.GetChange("attributes")
+	return userAttributesPatch(oldComment.(string), newComment.(string),
+		oldAttributes.(map[string]interface{}), newAttributes.(map[string]interface{}))
+}
+
+func alterUserAttributes(ctx context.Context, db *sql.DB, user, host string, patch map[string]interface{}) error {
+	if len(patch) == 0 {
+		return nil
+	}
+	attributeJSON, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("failed encoding user attributes: %w", err)
+	}
+	stmtSQL := "ALTER USER ?@? ATTRIBUTE ?"
+	log.Println("[DEBUG] Executing statement:", stmtSQL, "attributes:", string(attributeJSON))
+	if _, err := db.ExecContext(ctx, stmtSQL, user, host, string(attributeJSON)); err != nil {
+		return fmt.Errorf("failed setting user attributes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUser_attributes(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.21")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigAttributes("Reporting service", `team = "data", ticket = "OPS-1"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "comment", "Reporting service"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.%", "2"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.team", "data"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.#", "1"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.0.user", "jdoe"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.0.host", "example.com"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.0.comment", "Reporting service"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.0.attributes.ticket", "OPS-1"),
+				),
+			},
+			{
+				// Attributes added outside of Terraform show up as drift.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(`ALTER USER 'jdoe'@'example.com' ATTRIBUTE '{"expires": "2027-01-01"}'`); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccUserConfigAttributes("Reporting service", `team = "data", ticket = "OPS-1"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfigAttributes("", `team = "platform"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "comment", ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.%", "1"),
+					resource.TestCheckResourceAttr("mysql_user.test", "attributes.team", "platform"),
+					resource.TestCheckResourceAttr("data.mysql_users.team", "users.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigAttributes(comment, attributes string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = "jdoe"
+  host               = "example.com"
+  plaintext_password = "password"
+  comment            = %q
+  attributes         = { %s }
+}
+
+data "mysql_users" "team" {
+  pattern    = "jdoe"
+  attributes = { team = "data" }
+
+  depends_on = [mysql_user.test]
+}
+`, comment, attributes)
+}
+
+func TestUserAttributes(t *testing.T) {
+	comment, attributes, err := splitUserAttributes(`{"comment": "Reporting", "team": "data", "priority": 3}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment != "Reporting" || !reflect.DeepEqual(attributes, map[string]string{"team": "data", "priority": "3"}) {
+		t.Errorf("splitUserAttributes returned %q, %v", comment, attributes)
+	}
+	if _, _, err := splitUserAttributes("not json"); err == nil {
+		t.Errorf("expected an error for invalid JSON")
+	}
+
+	patch := userAttributesPatch("Reporting", "",
+		map[string]interface{}{"team": "data", "ticket": "OPS-1"},
+		map[string]interface{}{"team": "platform", "expires": "2027-01-01"})
+	expected := map[string]interface{}{"comment": nil, "team": "platform", "ticket": nil, "expires": "2027-01-01"}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("userAttributesPatch returned %v, expected %v", patch, expected)
+	}
+	if patch := userAttributesPatch("a", "a", map[string]interface{}{"team": "data"}, map[string]interface{}{"team": "data"}); len(patch) != 0 {
+		t.Errorf("expected an empty patch, got %v", patch)
+	}
+
+	if !matchUserAttributes("Reporting", attributes, map[string]interface{}{"team": "data", "comment": "Reporting"}) {
+		t.Errorf("expected the attributes to match")
+	}
+	if matchUserAttributes("", attributes, map[string]interface{}{"owner": ""}) {
+		t.Errorf("expected a missing attribute not to match")
+	}
+}
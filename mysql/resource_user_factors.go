@@ -0,0 +1,294 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// MySQL 8.0.27+ accounts can have up to three authentication factors, given as
+// IDENTIFIED WITH ... AND IDENTIFIED WITH ... in CREATE USER. Factor 1 is changed with ALTER USER
+// ... IDENTIFIED, factors 2 and 3 with ADD, MODIFY and DROP n FACTOR. A factor using a device,
+// such as authentication_webauthn, is registered with n FACTOR INITIATE REGISTRATION, which returns
+// a challenge the client has to finish with FINISH REGISTRATION.
+
+const maxUserFactors = 3
+
+func userFactorSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: maxUserFactors,
+		ConflictsWith: []string{
+			"plaintext_password", "password", "auth_plugin", "auth_string_hashed", "auth_string_hex", "aad_identity",
+		},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"plugin": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"plaintext_password": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: suppressHashedPasswordDiff,
+				},
+				"auth_string_hashed": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Sensitive:        true,
+					DiffSuppressFunc: NewEmptyStringSuppressFunc,
+				},
+				"initiate_registration": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"registration_challenge": {
+					Type:      schema.TypeString,
+					Computed:  true,
+					Sensitive: true,
+				},
+			},
+		},
+	}
+}
+
+// The passwords of factors are stored as their hash like plaintext_password. StateFunc isn't
+// applied to attributes within lists, so setUserFactorsState hashes them instead.
+func suppressHashedPasswordDiff(k, old, new string, d *schema.ResourceData) bool {
+	return new != "" && old == hashSum(new)
+}
+
+type userFactor struct {
+	Plugin               string
+	Password             string
+	AuthString           string
+	InitiateRegistration bool
+}
+
+func userFactorsFromList(list []interface{}) []userFactor {
+	factors := make([]userFactor, 0, len(list))
+	for _, item := range list {
+		m, _ := item.(map[string]interface{})
+		factor := userFactor{}
+		factor.Plugin, _ = m["plugin"].(string)
+		factor.Password, _ = m["plaintext_password"].(string)
+		factor.AuthString, _ = m["auth_string_hashed"].(string)
+		factor.InitiateRegistration, _ = m["initiate_registration"].(bool)
+		factors = append(factors, factor)
+	}
+	return factors
+}
+
+// identifiedSQL returns the IDENTIFIED WITH clause of the factor and its arguments.
+func (f userFactor) identifiedSQL() (string, []interface{}) {
+	stmtSQL := "IDENTIFIED WITH " + f.Plugin
+	return f.secretSQL(stmtSQL)
+}
+
+func (f userFactor) secretSQL(stmtSQL string) (string, []interface{}) {
+	switch {
+	case f.Password != "":
+		return stmtSQL + " BY ?", []interface{}{f.Password}
+	case f.AuthString != "":
+		return stmtSQL + " AS ?", []interface{}{f.AuthString}
+	}
+	return stmtSQL, nil
+}
+
+// secretChanged tells whether the configured secret of the factor differs from the one in the
+// old state, where the password is stored as its hash.
+func (f userFactor) secretChanged(old userFactor) bool {
+	if f.Password != "" {
+		return f.Password != old.Password && hashSum(f.Password) != old.Password
+	}
+	return f.AuthString != "" && f.AuthString != old.AuthString
+}
+
+// userFactorsSQL returns the authentication of CREATE USER with all the factors.
+func userFactorsSQL(factors []userFactor) (string, []interface{}) {
+	clauses := []string{}
+	args := []interface{}{}
+	for _, factor := range factors {
+		clause, factorArgs := factor.identifiedSQL()
+		clauses = append(clauses, clause)
+		args = append(args, factorArgs...)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+type userFactorStatement struct {
+	// SQL follows ALTER USER ?@?.
+	SQL  string
+	Args []interface{}
+}
+
+// userFactorStatements returns the ALTER USER clauses turning the old factors into the new ones.
+// A factor whose plugin changes is dropped and added again, together with the factors after it,
+// as factors can only be dropped from the last one. Without new factors, factor 1 is left to the
+// other arguments of the user.
+func userFactorStatements(old, new []userFactor) []userFactorStatement {
+	replaceFrom := 1
+	for replaceFrom < len(old) && replaceFrom < len(new) && old[replaceFrom].Plugin == new[replaceFrom].Plugin {
+		replaceFrom++
+	}
+
+	statements := []userFactorStatement{}
+	for i := len(old) - 1; i >= 1; i-- {
+		if i >= len(new) || i >= replaceFrom {
+			statements = append(statements, userFactorStatement{SQL: fmt.Sprintf("DROP %d FACTOR", i+1)})
+		}
+	}
+
+	if len(new) > 0 {
+		first := new[0]
+		if len(old) == 0 || old[0].Plugin != first.Plugin || first.secretChanged(old[0]) {
+			stmtSQL, args := first.identifiedSQL()
+			statements = append(statements, userFactorStatement{SQL: stmtSQL, Args: args})
+		}
+	}
+
+	for i := 1; i < len(new); i++ {
+		factor := new[i]
+		if i >= len(old) || i >= replaceFrom {
+			stmtSQL, args := factor.identifiedSQL()
+			statements = append(statements, userFactorStatement{SQL: fmt.Sprintf("ADD %d FACTOR %s", i+1, stmtSQL), Args: args})
+		} else if factor.secretChanged(old[i]) {
+			stmtSQL, args := factor.secretSQL(fmt.Sprintf("MODIFY %d FACTOR IDENTIFIED", i+1))
+			statements = append(statements, userFactorStatement{SQL: stmtSQL, Args: args})
+		}
+	}
+	return statements
+}
+
+// userFactorRegistrations returns the numbers of the factors to register: the new or replaced
+// factors asking for it, and the ones that just started asking for it.
+func userFactorRegistrations(old, new []userFactor) []int {
+	replaced := map[int]bool{}
+	for _, statement := range userFactorStatements(old, new) {
+		var n int
+		if _, err := fmt.Sscanf(statement.SQL, "ADD %d FACTOR", &n); err == nil {
+			replaced[n-1] = true
+		}
+	}
+
+	numbers := []int{}
+	for i := 1; i < len(new); i++ {
+		if new[i].InitiateRegistration && (replaced[i] || !old[i].InitiateRegistration) {
+			numbers = append(numbers, i+1)
+		}
+	}
+	return numbers
+}
+
+func alterUserFactors(ctx context.Context, db *sql.DB, user, host string, statements []userFactorStatement) error {
+	for _, statement := range statements {
+		stmtSQL := "ALTER USER ?@? " + statement.SQL
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		args := append([]interface{}{user, host}, statement.Args...)
+		if _, err := db.ExecContext(ctx, stmtSQL, args...); err != nil {
+			return fmt.Errorf("failed changing authentication factors: %w", err)
+		}
+	}
+	return nil
+}
+
+// registerUserFactors initiates the registration of the given factors and returns the challenges
+// by factor number.
+func registerUserFactors(ctx context.Context, db *sql.DB, user, host string, numbers []int) (map[int]string, error) {
+	challenges := map[int]string{}
+	for _, n := range numbers {
+		stmtSQL := fmt.Sprintf("ALTER USER ?@? %d FACTOR INITIATE REGISTRATION", n)
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		var challenge string
+		if err := db.QueryRowContext(ctx, stmtSQL, user, host).Scan(&challenge); err != nil {
+			return challenges, fmt.Errorf("failed initiating registration of factor %d: %w", n, err)
+		}
+		challenges[n] = challenge
+	}
+	return challenges, nil
+}
+
+// setUserFactorsState stores the applied factors with hashed passwords and the new challenges.
+func setUserFactorsState(d *schema.ResourceData, challenges map[int]string) {
+	oldList, newList := d.GetChange("authentication_factor")
+	old := oldList.([]interface{})
+	list := []interface{}{}
+	for i, item := range newList.([]interface{}) {
+		m := map[string]interface{}{}
+		for key, value := range item.(map[string]interface{}) {
+			m[key] = value
+		}
+		if password, _ := m["plaintext_password"].(string); password != "" {
+			oldPassword := ""
+			if i < len(old) {
+				oldPassword, _ = old[i].(map[string]interface{})["plaintext_password"].(string)
+			}
+			if password != oldPassword {
+				m["plaintext_password"] = hashSum(password)
+			}
+		}
+		if challenge, ok := challenges[i+1]; ok {
+			m["registration_challenge"] = challenge
+		}
+		list = append(list, m)
+	}
+	d.Set("authentication_factor", list)
+}
+
+var kReUserFactor = regexp.MustCompile("AND IDENTIFIED WITH ['`]([^'`]*)['`](?: AS (?:'((?:.*?[^\\\\])?)'|(0x[0-9A-Fa-f]+)))?")
+
+// parseUserFactors returns the factors after the first one from the AND IDENTIFIED WITH clauses
+// of SHOW CREATE USER.
+func parseUserFactors(clauses string) []userFactor {
+	factors := []userFactor{}
+	for _, m := range kReUserFactor.FindAllStringSubmatch(clauses, -1) {
+		factors = append(factors, userFactor{Plugin: m[1], AuthString: m[2]})
+	}
+	return factors
+}
+
+// setUserFactors sets the factors read from the server. They are only kept when configured or when
+// the account has more than one factor. Passwords and registrations can't be read back, so they
+// are kept from the state for factors whose plugin didn't change.
+func setUserFactors(d *schema.ResourceData, factors []userFactor) {
+	state := d.Get("authentication_factor").([]interface{})
+	if len(state) == 0 && len(factors) < 2 {
+		return
+	}
+
+	list := make([]interface{}, 0, len(factors))
+	for i, factor := range factors {
+		m := map[string]interface{}{
+			"plugin":             factor.Plugin,
+			"auth_string_hashed": factor.AuthString,
+		}
+		if i < len(state) {
+			if old, ok := state[i].(map[string]interface{}); ok && old["plugin"] == factor.Plugin {
+				m["plaintext_password"] = old["plaintext_password"]
+				m["initiate_registration"] = old["initiate_registration"]
+				m["registration_challenge"] = old["registration_challenge"]
+			}
+		}
+		list = append(list, m)
+	}
+	d.Set("authentication_factor", list)
+}
+
+func validateUserFactors(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for i, factor := range userFactorsFromList(d.Get("authentication_factor").([]interface{})) {
+		if factor.Password != "" && factor.AuthString != "" {
+			return fmt.Errorf("authentication factor %d can't have both plaintext_password and auth_string_hashed", i+1)
+		}
+		if factor.InitiateRegistration && i == 0 {
+			return fmt.Errorf("only authentication factors 2 and 3 can be registered")
+		}
+	}
+	return nil
+}
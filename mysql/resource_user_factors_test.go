@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUser_authenticationFactors(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.27")
+			testAccPreCheckSkipMissingPlugin(t, "auth_socket")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigFactors("password", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "authentication_factor.#", "2"),
+					resource.TestCheckResourceAttr("mysql_user.test", "authentication_factor.0.plugin", "caching_sha2_password"),
+					resource.TestCheckResourceAttr("mysql_user.test", "authentication_factor.1.plugin", "auth_socket"),
+				),
+			},
+			{
+				Config: testAccUserConfigFactors("password2", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "authentication_factor.#", "2"),
+				),
+			},
+			{
+				Config: testAccUserConfigFactors("password2", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "authentication_factor.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccPreCheckSkipMissingPlugin(t *testing.T, plugin string) {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		t.Fatalf("Cannot connect to DB (SkipMissingPlugin): %v", err)
+		return
+	}
+
+	var status string
+	if err := db.QueryRow("SELECT PLUGIN_STATUS FROM information_schema.PLUGINS WHERE PLUGIN_NAME = ?", plugin).Scan(&status); err != nil || status != "ACTIVE" {
+		t.Skipf("Skip on servers without the %s plugin", plugin)
+	}
+}
+
+func testAccUserConfigFactors(password string, secondFactor bool) string {
+	factor := ""
+	if secondFactor {
+		factor = `
+  authentication_factor {
+    plugin = "auth_socket"
+  }`
+	}
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "example.com"
+
+  authentication_factor {
+    plugin             = "caching_sha2_password"
+    plaintext_password = %q
+  }%s
+}
+`, password, factor)
+}
+
+func TestUserFactors(t *testing.T) {
+	password := userFactor{Plugin: "caching_sha2_password", Password: "secret"}
+	ldap := userFactor{Plugin: "authentication_ldap_simple", AuthString: "uid=jdoe"}
+	webauthn := userFactor{Plugin: "authentication_webauthn", InitiateRegistration: true}
+
+	stmtSQL, args := userFactorsSQL([]userFactor{password, ldap, webauthn})
+	expected := "IDENTIFIED WITH caching_sha2_password BY ? AND IDENTIFIED WITH authentication_ldap_simple AS ? AND IDENTIFIED WITH authentication_webauthn"
+	if stmtSQL != expected || !reflect.DeepEqual(args, []interface{}{"secret", "uid=jdoe"}) {
+		t.Errorf("userFactorsSQL returned %q %v, expected %q", stmtSQL, args, expected)
+	}
+
+	statementsSQL := func(statements []userFactorStatement) []string {
+		result := []string{}
+		for _, statement := range statements {
+			result = append(result, statement.SQL)
+		}
+		return result
+	}
+
+	// The old password is in the state as its hash.
+	hashed := password
+	hashed.Password = hashSum(password.Password)
+	tests := []struct {
+		name     string
+		old, new []userFactor
+		expected []string
+	}{
+		{"unchanged", []userFactor{hashed, ldap}, []userFactor{password, ldap}, []string{}},
+		{"add", []userFactor{hashed}, []userFactor{password, ldap, webauthn},
+			[]string{"ADD 2 FACTOR IDENTIFIED WITH authentication_ldap_simple AS ?", "ADD 3 FACTOR IDENTIFIED WITH authentication_webauthn"}},
+		{"drop", []userFactor{hashed, ldap, webauthn}, []userFactor{password}, []string{"DROP 3 FACTOR", "DROP 2 FACTOR"}},
+		{"modify", []userFactor{hashed, ldap}, []userFactor{{Plugin: "caching_sha2_password", Password: "new"}, {Plugin: "authentication_ldap_simple", AuthString: "uid=john"}},
+			[]string{"IDENTIFIED WITH caching_sha2_password BY ?", "MODIFY 2 FACTOR IDENTIFIED AS ?"}},
+		{"replace", []userFactor{hashed, ldap, webauthn}, []userFactor{password, webauthn},
+			[]string{"DROP 3 FACTOR", "DROP 2 FACTOR", "ADD 2 FACTOR IDENTIFIED WITH authentication_webauthn"}},
+		{"from single factor", nil, []userFactor{password, ldap},
+			[]string{"IDENTIFIED WITH caching_sha2_password BY ?", "ADD 2 FACTOR IDENTIFIED WITH authentication_ldap_simple AS ?"}},
+		{"to single factor", []userFactor{hashed, ldap}, nil, []string{"DROP 2 FACTOR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := statementsSQL(userFactorStatements(tt.old, tt.new)); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("userFactorStatements returned %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+
+	if numbers := userFactorRegistrations([]userFactor{hashed, ldap}, []userFactor{password, ldap, webauthn}); !reflect.DeepEqual(numbers, []int{3}) {
+		t.Errorf("userFactorRegistrations returned %v, expected [3]", numbers)
+	}
+	if numbers := userFactorRegistrations([]userFactor{hashed, webauthn}, []userFactor{password, webauthn}); len(numbers) != 0 {
+		t.Errorf("userFactorRegistrations returned %v for a registered factor", numbers)
+	}
+
+	factors := parseUserFactors("AND IDENTIFIED WITH 'authentication_ldap_simple' AS 'uid=jdoe' AND IDENTIFIED WITH 'authentication_webauthn' ")
+	if !reflect.DeepEqual(factors, []userFactor{{Plugin: "authentication_ldap_simple", AuthString: "uid=jdoe"}, {Plugin: "authentication_webauthn"}}) {
+		t.Errorf("parseUserFactors returned %v", factors)
+	}
+}
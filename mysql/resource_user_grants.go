@@ -0,0 +1,471 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceUserGrants manages the complete set of grants of one user or role. Unlike mysql_grant,
+// grants that are not listed are revoked.
+func resourceUserGrants() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateUserGrants,
+		UpdateContext: UpdateUserGrants,
+		ReadContext:   ReadUserGrants,
+		DeleteContext: DeleteUserGrants,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportUserGrants,
+		},
+		CustomizeDiff: validateUserGrantsPrivileges,
+
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"role"},
+			},
+
+			"host": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       "localhost",
+				ConflictsWith: []string{"role"},
+			},
+
+			"role": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user", "host"},
+			},
+
+			"grant": userGrantsGrantSchema(),
+
+			"roles": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString, StateFunc: normalizeRoleReference},
+				Set:      hashRoleReference,
+			},
+		},
+	}
+}
+
+// userGrantsGrantSchema is the grant block of resources managing all grants of an account.
+func userGrantsGrantSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"database": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"table": {
+					Type:     schema.TypeString,
+					Optional: true,
+					Default:  "*",
+				},
+				"privileges": {
+					Type:     schema.TypeSet,
+					Required: true,
+					MinItems: 1,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Set:      schema.HashString,
+				},
+				"grant": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
+func userGrantsAccount(d *schema.ResourceData) (UserOrRole, error) {
+	if role := d.Get("role").(string); role != "" {
+		return parseRoleReference(role), nil
+	}
+	user := d.Get("user").(string)
+	if user == "" {
+		return UserOrRole{}, fmt.Errorf("one of user/host or role is required")
+	}
+	return UserOrRole{Name: user, Host: d.Get("host").(string)}, nil
+}
+
+// userGrantKey identifies the object a grant is on, so grants from the configuration and from
+// SHOW GRANTS can be matched.
+func userGrantKey(grant MySQLGrant) string {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		table := g.Table
+		if table == "" {
+			table = "*"
+		}
+		return fmt.Sprintf("%s.%s", strings.Trim(g.Database, "`"), table)
+	case *ProcedurePrivilegeGrant:
+		return fmt.Sprintf("%s %s.%s", strings.ToUpper(string(g.ObjectT)), strings.Trim(g.Database, "`"), g.CallableName)
+	}
+	return ""
+}
+
+// expandUserGrant parses one grant block the same way mysql_grant parses its database and table.
+func expandUserGrant(m map[string]interface{}, userOrRole UserOrRole) MySQLGrant {
+	database := m["database"].(string)
+	table := m["table"].(string)
+	privileges := normalizePerms(setToArray(m["privileges"]))
+	grantOption := m["grant"].(bool)
+
+	if matches := kReProcedureWithDatabase.FindStringSubmatch(database); matches != nil {
+		return &ProcedurePrivilegeGrant{
+			Database:     strings.Trim(matches[2], "`"),
+			ObjectT:      ObjectT(strings.ToUpper(matches[1])),
+			CallableName: strings.Trim(matches[3], "`"),
+			Privileges:   privileges,
+			Grant:        grantOption,
+			UserOrRole:   userOrRole,
+		}
+	}
+	if matches := kReProcedureWithoutDatabase.FindStringSubmatch(database); matches != nil {
+		return &ProcedurePrivilegeGrant{
+			Database:     strings.Trim(matches[2], "`"),
+			ObjectT:      ObjectT(strings.ToUpper(matches[1])),
+			CallableName: table,
+			Privileges:   privileges,
+			Grant:        grantOption,
+			UserOrRole:   userOrRole,
+		}
+	}
+	return &TablePrivilegeGrant{
+		Database:   database,
+		Table:      table,
+		Privileges: privileges,
+		Grant:      grantOption,
+		UserOrRole: userOrRole,
+	}
+}
+
+// expandUserGrants returns the grants from the configuration keyed by userGrantKey.
+func expandUserGrants(d *schema.ResourceData, userOrRole UserOrRole) (map[string]MySQLGrant, error) {
+	grants := map[string]MySQLGrant{}
+	for _, elem := range d.Get("grant").(*schema.Set).List() {
+		grant := expandUserGrant(elem.(map[string]interface{}), userOrRole)
+		key := userGrantKey(grant)
+		if _, ok := grants[key]; ok {
+			return nil, fmt.Errorf("grant on %s is listed more than once", key)
+		}
+		grants[key] = grant
+	}
+	return grants, nil
+}
+
+// currentUserGrants reads all grants of the account, combining the rows of each object, and the granted roles.
+func currentUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) (map[string]MySQLGrant, []string, error) {
+	allGrants, err := showAccountGrants(ctx, db, userOrRole)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	grants := map[string]MySQLGrant{}
+	roles := []string{}
+	for _, grant := range allGrants {
+		if roleGrant, ok := grant.(*RoleGrant); ok {
+			roles = append(roles, roleGrant.Roles...)
+			continue
+		}
+		key := userGrantKey(grant)
+		if existing, ok := grants[key]; ok {
+			combined, err := combineGrants(existing, grant)
+			if err != nil {
+				return nil, nil, err
+			}
+			grant = combined
+		}
+		grants[key] = grant
+	}
+
+	for _, grant := range grants {
+		switch g := grant.(type) {
+		case *TablePrivilegeGrant:
+			g.Privileges = normalizePerms(g.Privileges)
+		case *ProcedurePrivilegeGrant:
+			g.Privileges = normalizePerms(g.Privileges)
+		}
+	}
+	sort.Strings(roles)
+	return grants, roles, nil
+}
+
+// withGrantOption returns a copy of the grant with the grant option set to grantOption.
+func withGrantOption(grant MySQLGrant, grantOption bool) MySQLGrant {
+	switch g := grant.(type) {
+	case *TablePrivilegeGrant:
+		copied := *g
+		copied.Grant = grantOption
+		return &copied
+	case *ProcedurePrivilegeGrant:
+		copied := *g
+		copied.Grant = grantOption
+		return &copied
+	}
+	return grant
+}
+
+func grantPrivileges(grant MySQLGrant) []string {
+	if grantWithPriv, ok := grant.(MySQLGrantWithPrivileges); ok {
+		return grantWithPriv.GetPrivileges()
+	}
+	return nil
+}
+
+func differenceOf(a, b []string) []string {
+	result := []string{}
+	for _, item := range a {
+		if !slices.Contains(b, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// userGrantsStatements returns the statements turning the current grants into the desired ones.
+// Grants come first and revokes last, as in privilegeUpdateStatements, so privileges that are kept
+// or moved between objects aren't missing while the statements run.
+func userGrantsStatements(userOrRole UserOrRole, current map[string]MySQLGrant, currentRoles []string, desired map[string]MySQLGrant, desiredRoles []string) ([]string, error) {
+	grants := []string{}
+	updates := []string{}
+	revokes := []string{}
+
+	keys := []string{}
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range desired {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		currentGrant, hasCurrent := current[key]
+		desiredGrant, hasDesired := desired[key]
+		switch {
+		case !hasDesired:
+			revokes = append(revokes, currentGrant.SQLRevokeStatement())
+		case !hasCurrent:
+			grants = append(grants, desiredGrant.SQLGrantStatement())
+		default:
+			statements, _, err := privilegeUpdateStatements(desiredGrant, grantPrivileges(currentGrant), grantPrivileges(desiredGrant))
+			if err != nil {
+				return nil, fmt.Errorf("failed updating privileges on %s: %w", key, err)
+			}
+			updates = append(updates, statements...)
+			// The grant option is changed after the privileges, which may have been revoked and granted again.
+			if desiredGrant.GrantOption() && !currentGrant.GrantOption() {
+				updates = append(updates, desiredGrant.SQLGrantStatement())
+			}
+			if currentGrant.GrantOption() && !desiredGrant.GrantOption() {
+				revoker := currentGrant.(PrivilegesPartiallyRevocable)
+				updates = append(updates, revoker.SQLPartialRevokePrivilegesStatement([]string{}))
+			}
+		}
+	}
+
+	if missing := differenceOf(desiredRoles, currentRoles); len(missing) > 0 {
+		grants = append(grants, (&RoleGrant{Roles: missing, UserOrRole: userOrRole}).SQLGrantStatement())
+	}
+	if extra := differenceOf(currentRoles, desiredRoles); len(extra) > 0 {
+		revokes = append(revokes, (&RoleGrant{Roles: extra, UserOrRole: userOrRole}).SQLRevokeStatement())
+	}
+
+	return append(append(grants, updates...), revokes...), nil
+}
+
+func applyUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	userOrRole, err := userGrantsAccount(d)
+	if err != nil {
+		return err
+	}
+	desired, err := expandUserGrants(d, userOrRole)
+	if err != nil {
+		return err
+	}
+	desiredRoles := normalizeRoleReferences(setToArray(d.Get("roles")))
+
+	grantCreateMutex.Lock(userOrRole.IDString())
+	defer grantCreateMutex.Unlock(userOrRole.IDString())
+	defer invalidateUserGrants(db, userOrRole)
+
+	current, currentRoles, err := currentUserGrants(ctx, db, userOrRole)
+	if err != nil {
+		return fmt.Errorf("failed reading grants of %s: %w", userOrRole.SQLString(), err)
+	}
+
+	statements, err := userGrantsStatements(userOrRole, current, currentRoles, desired, desiredRoles)
+	if err != nil {
+		return err
+	}
+	for _, stmtSQL := range statements {
+		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		if _, err := db.ExecContext(ctx, stmtSQL); err != nil {
+			return fmt.Errorf("error running SQL (%s): %w", stmtSQL, err)
+		}
+	}
+	return nil
+}
+
+func CreateUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := applyUserGrants(ctx, d, meta); err != nil {
+		return diag.Errorf("failed creating grants: %v", err)
+	}
+
+	userOrRole, _ := userGrantsAccount(d)
+	d.SetId(formatAccountId(userOrRole))
+
+	return ReadUserGrants(ctx, d, meta)
+}
+
+func UpdateUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChanges("grant", "roles") {
+		if err := applyUserGrants(ctx, d, meta); err != nil {
+			return diag.Errorf("failed updating grants: %v", err)
+		}
+	}
+
+	return ReadUserGrants(ctx, d, meta)
+}
+
+func ReadUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	userOrRole, err := userGrantsAccount(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current, currentRoles, err := currentUserGrants(ctx, db, userOrRole)
+	if isNonExistingGrant(err) {
+		log.Printf("[WARN] Account %s not found; removing grants from state", userOrRole.SQLString())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("failed reading grants of %s: %v", userOrRole.SQLString(), err)
+	}
+
+	d.Set("grant", flattenUserGrants(current, d.Get("grant").(*schema.Set).List(), userOrRole))
+	d.Set("roles", currentRoles)
+
+	return nil
+}
+
+// flattenUserGrants builds the grant blocks. Blocks from the current state that are equal after
+// normalization are kept as they are, so e.g. "select" doesn't show a diff against SELECT.
+func flattenUserGrants(grants map[string]MySQLGrant, stateBlocks []interface{}, userOrRole UserOrRole) []interface{} {
+	stateByKey := map[string]map[string]interface{}{}
+	for _, elem := range stateBlocks {
+		m := elem.(map[string]interface{})
+		stateByKey[userGrantKey(expandUserGrant(m, userOrRole))] = m
+	}
+
+	keys := make([]string, 0, len(grants))
+	for key := range grants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := []interface{}{}
+	for _, key := range keys {
+		grant := grants[key]
+		if m, ok := stateByKey[key]; ok {
+			stateGrant := expandUserGrant(m, userOrRole)
+			if reflect.DeepEqual(grantPrivileges(stateGrant), grantPrivileges(grant)) && stateGrant.GrantOption() == grant.GrantOption() {
+				result = append(result, m)
+				continue
+			}
+		}
+
+		m := map[string]interface{}{
+			"privileges": grantPrivileges(grant),
+			"grant":      grant.GrantOption(),
+		}
+		switch g := grant.(type) {
+		case *TablePrivilegeGrant:
+			m["database"] = g.Database
+			m["table"] = g.Table
+		case *ProcedurePrivilegeGrant:
+			m["database"] = fmt.Sprintf("%s %s.%s", g.ObjectT, strings.Trim(g.Database, "`"), g.CallableName)
+			m["table"] = "*"
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func DeleteUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.Set("grant", []interface{}{})
+	d.Set("roles", []interface{}{})
+	if err := applyUserGrants(ctx, d, meta); err != nil {
+		if isNonExistingGrant(err) {
+			return nil
+		}
+		return diag.Errorf("failed revoking grants: %v", err)
+	}
+
+	return nil
+}
+
+// userGrantsRoleImportPrefix marks the import ID of a role, as ROLE@HOST can't be told apart
+// from USER@HOST otherwise.
+const userGrantsRoleImportPrefix = "role:"
+
+func ImportUserGrants(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	if ref, ok := strings.CutPrefix(id, userGrantsRoleImportPrefix); ok {
+		role := parseRoleReference(ref)
+		if role.Name == "" {
+			return nil, fmt.Errorf("wrong ID format %s (expected role:ROLE or role:ROLE@HOST)", id)
+		}
+		d.Set("role", formatRoleReference(role))
+	} else if user, host, ok := parseUserHostId(id); ok {
+		if user == "" {
+			return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST, ROLE or role:ROLE@HOST)", id)
+		}
+		d.Set("user", user)
+		d.Set("host", host)
+	} else {
+		d.Set("role", unescapeIdPart(id))
+	}
+
+	readDiags := ReadUserGrants(ctx, d, meta)
+	if readDiags.HasError() {
+		return nil, fmt.Errorf("failed reading grants: %v", readDiags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("user or role %s does not exist", id)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
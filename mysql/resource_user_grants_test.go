@@ -0,0 +1,206 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccUserGrants_basic(t *testing.T) {
+	dbName := fmt.Sprintf("tf-test-%d", rand.Intn(100))
+	userName := fmt.Sprintf("jdoe-%s", dbName)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserGrantsConfig(dbName, `"SELECT", "INSERT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user_grants.test", "grant.#", "1"),
+					testAccUserGrantsPrivilege(userName, dbName, "INSERT", true),
+				),
+			},
+			{
+				// A grant made outside of Terraform is drift and gets revoked.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec(fmt.Sprintf("GRANT DELETE ON `%s`.* TO '%s'@'example.com'", dbName, userName)); err != nil {
+						t.Fatal(err)
+					}
+					invalidateAllUserGrants(db)
+				},
+				Config: testAccUserGrantsConfig(dbName, `"SELECT"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user_grants.test", "grant.#", "1"),
+					testAccUserGrantsPrivilege(userName, dbName, "SELECT", true),
+					testAccUserGrantsPrivilege(userName, dbName, "INSERT", false),
+					testAccUserGrantsPrivilege(userName, dbName, "DELETE", false),
+				),
+			},
+			{
+				Config:            testAccUserGrantsConfig(dbName, `"SELECT"`),
+				ResourceName:      "mysql_user_grants.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     fmt.Sprintf("%s@example.com", userName),
+			},
+		},
+	})
+}
+
+func TestAccUserGrants_roleWithHost(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQL8(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccGrantCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mysql_database" "test" {
+  name = "tf-test-user-grants-role"
+}
+
+resource "mysql_role" "test" {
+  name = "tf-test-user-grants-role"
+  host = "10.%"
+}
+
+resource "mysql_user_grants" "test" {
+  role = "${mysql_role.test.name}@${mysql_role.test.host}"
+
+  grant {
+    database   = mysql_database.test.name
+    privileges = ["SELECT"]
+  }
+}
+`,
+			},
+			{
+				// Without the prefix, the ID would be taken as a user.
+				ResourceName:      "mysql_user_grants.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     "role:tf-test-user-grants-role@10.%",
+			},
+		},
+	})
+}
+
+func testAccUserGrantsPrivilege(userName, dbName, privilege string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		grants, _, err := currentUserGrants(ctx, db, UserOrRole{Name: userName, Host: "example.com"})
+		if err != nil {
+			return err
+		}
+		found := false
+		if grant, ok := grants[dbName+".*"]; ok {
+			for _, priv := range grantPrivileges(grant) {
+				if priv == privilege {
+					found = true
+				}
+			}
+		}
+		if found != expected {
+			return fmt.Errorf("expected %s on %s to exist: %t, but it exists: %t", privilege, dbName, expected, found)
+		}
+		return nil
+	}
+}
+
+func testAccUserGrantsConfig(dbName, privileges string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_user" "test" {
+  user = "jdoe-%s"
+  host = "example.com"
+}
+
+resource "mysql_user_grants" "test" {
+  user = mysql_user.test.user
+  host = mysql_user.test.host
+
+  grant {
+    database   = mysql_database.test.name
+    privileges = [%s]
+  }
+}
+`, dbName, dbName, privileges)
+}
+
+func TestUserGrantsStatements(t *testing.T) {
+	account := UserOrRole{Name: "jdoe", Host: "%"}
+	current := map[string]MySQLGrant{
+		"app.*":   &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"INSERT", "SELECT"}, Grant: true, UserOrRole: account},
+		"other.*": &TablePrivilegeGrant{Database: "other", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: account},
+	}
+	desired := map[string]MySQLGrant{
+		"app.*": &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT", "UPDATE"}, UserOrRole: account},
+		"PROCEDURE app.do_it": &ProcedurePrivilegeGrant{Database: "app", ObjectT: "PROCEDURE", CallableName: "do_it",
+			Privileges: []string{"EXECUTE"}, UserOrRole: account},
+	}
+
+	statements, err := userGrantsStatements(account, current, []string{"admin", "reader"}, desired, []string{"reader", "writer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"GRANT EXECUTE ON PROCEDURE `app`.`do_it` TO 'jdoe'@'%'",
+		"GRANT 'writer' TO 'jdoe'@'%'",
+		"GRANT UPDATE ON `app`.* TO 'jdoe'@'%'",
+		"REVOKE INSERT ON `app`.* FROM 'jdoe'@'%'",
+		"REVOKE GRANT OPTION ON `app`.* FROM 'jdoe'@'%'",
+		"REVOKE SELECT ON `other`.* FROM 'jdoe'@'%'",
+		"REVOKE 'admin' FROM 'jdoe'@'%'",
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("statements are %q, expected %q", statements, expected)
+	}
+
+	if statements, _ := userGrantsStatements(account, current, nil, current, nil); len(statements) != 0 {
+		t.Errorf("expected no statements without changes, got %q", statements)
+	}
+
+	// Moving from ALL PRIVILEGES to some of them has to revoke first.
+	all := map[string]MySQLGrant{"app.*": &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"ALL PRIVILEGES"}, UserOrRole: account}}
+	some := map[string]MySQLGrant{"app.*": &TablePrivilegeGrant{Database: "app", Table: "*", Privileges: []string{"SELECT"}, UserOrRole: account}}
+	statements, err = userGrantsStatements(account, all, nil, some, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{
+		"REVOKE ALL PRIVILEGES ON `app`.* FROM 'jdoe'@'%'",
+		"GRANT SELECT ON `app`.* TO 'jdoe'@'%'",
+	}
+	if !reflect.DeepEqual(statements, expected) {
+		t.Errorf("statements are %q, expected %q", statements, expected)
+	}
+}
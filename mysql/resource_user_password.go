@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 
@@ -10,14 +11,19 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceUserPassword() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		CreateContext: SetUserPassword,
 		UpdateContext: SetUserPassword,
 		ReadContext:   ReadUserPassword,
 		DeleteContext: DeleteUserPassword,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportUserPassword,
+		},
+		SchemaVersion: 1,
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:     schema.TypeString,
@@ -31,17 +37,75 @@ func resourceUserPassword() *schema.Resource {
 				Default:  "localhost",
 			},
 			"plaintext_password": {
+				Type:          schema.TypeString,
+				Sensitive:     true,
+				Optional:      true,
+				ConflictsWith: []string{"random_password"},
+			},
+
+			"random_password": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
+			"random_password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"random_password"},
+				ValidateFunc: validation.IntBetween(minRandomPasswordLength, maxRandomPasswordLength),
+			},
+
+			"generated_password": {
 				Type:      schema.TypeString,
+				Computed:  true,
 				Sensitive: true,
-				Optional:  true,
+			},
+
+			"keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 
 			"retain_old_password": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+
+			"verify_password": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
 		},
 	}
+	r.StateUpgraders = idStateUpgraders(resourceUserPasswordV0(), resourceUserPasswordStateUpgradeV0)
+	return r
+}
+
+// resourceUserPasswordV0 is the schema of mysql_user_password at version 0.
+func resourceUserPasswordV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"user":                {Type: schema.TypeString, Required: true},
+			"host":                {Type: schema.TypeString, Optional: true},
+			"plaintext_password":  {Type: schema.TypeString, Optional: true},
+			"retain_old_password": {Type: schema.TypeBool, Optional: true},
+		},
+	}
+}
+
+// resourceUserPasswordStateUpgradeV0 escapes the user and host in the ID, which used to be USER@HOST as is.
+func resourceUserPasswordStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	user, _ := rawState["user"].(string)
+	host, _ := rawState["host"].(string)
+	if user == "" {
+		return rawState, nil
+	}
+	rawState["id"] = formatUserHostId(user, host)
+	return rawState, nil
 }
 
 func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -50,6 +114,18 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 		return diag.FromErr(err)
 	}
 
+	retainPassword := d.Get("retain_old_password").(bool)
+	if retainPassword {
+		err := checkRetainCurrentPasswordSupport(ctx, meta)
+		if err != nil {
+			return diag.Errorf("cannot use retain_current_password: %v", err)
+		}
+	}
+
+	if d.Get("random_password").(bool) {
+		return setRandomUserPassword(ctx, d, meta, db, retainPassword)
+	}
+
 	uuid, err := uuid.NewV4()
 	if err != nil {
 		return diag.Errorf("failed getting UUID: %v", err)
@@ -61,14 +137,6 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 		d.Set("plaintext_password", password)
 	}
 
-	retainPassword := d.Get("retain_old_password").(bool)
-	if retainPassword {
-		err := checkRetainCurrentPasswordSupport(ctx, meta)
-		if err != nil {
-			return diag.Errorf("cannot use retain_current_password: %v", err)
-		}
-	}
-
 	stmtSQL, err := getSetPasswordStatement(ctx, meta, retainPassword)
 	if err != nil {
 		return diag.Errorf("failed getting password statement: %v", err)
@@ -80,10 +148,28 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 	if err != nil {
 		return diag.Errorf("failed executing change statement: %v", err)
 	}
-	user := fmt.Sprintf("%s@%s",
-		d.Get("user").(string),
-		d.Get("host").(string))
-	d.SetId(user)
+	d.SetId(formatUserHostId(d.Get("user").(string), d.Get("host").(string)))
+	return nil
+}
+
+// setRandomUserPassword lets the server generate the password. It is only generated when the
+// resource is created, which happens again when random_password_length or keepers change.
+func setRandomUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}, db *sql.DB, retainPassword bool) diag.Diagnostics {
+	if !d.IsNewResource() {
+		return nil
+	}
+	if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+		return diag.Errorf("cannot use random_password: %v", err)
+	}
+
+	user := d.Get("user").(string)
+	host := d.Get("host").(string)
+	password, err := execRandomPassword(ctx, db, d.Get("random_password_length").(int), randomPasswordStatement(retainPassword), user, host)
+	if err != nil {
+		return diag.Errorf("failed generating password: %v", err)
+	}
+	d.Set("generated_password", password)
+	d.SetId(formatUserHostId(user, host))
 	return nil
 }
 
@@ -94,6 +180,22 @@ func canReadPassword(ctx context.Context, meta interface{}) (bool, error) {
 }
 
 func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("verify_password").(bool) {
+		password := d.Get("plaintext_password").(string)
+		if d.Get("random_password").(bool) {
+			password = d.Get("generated_password").(string)
+		}
+		if password == "" {
+			return nil
+		}
+		verified, diags := verifyUserPassword(ctx, meta, d.Get("user").(string), d.Get("host").(string), password)
+		if !verified {
+			// Like below, the password is set again by recreating the resource.
+			d.SetId("")
+		}
+		return diags
+	}
+
 	canRead, err := canReadPassword(ctx, meta)
 	if err != nil {
 		return diag.Errorf("cannot get whether we can read password: %v", err)
@@ -101,6 +203,10 @@ func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interfac
 	if !canRead {
 		return nil
 	}
+	if d.Get("plaintext_password").(string) == "" {
+		// Freshly imported; there is nothing to compare against yet.
+		return nil
+	}
 
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -148,6 +254,32 @@ func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interfac
 	return nil
 }
 
+func ImportUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	user, host, ok := parseUserHostId(d.Id())
+	if !ok || user == "" {
+		return nil, fmt.Errorf("wrong ID format %s (expected USER@HOST)", d.Id())
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := userExists(ctx, db, user, host)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("user %s does not exist", d.Id())
+	}
+
+	// The password can't be read back, so the next apply sets the one from the configuration.
+	d.Set("user", user)
+	d.Set("host", host)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func DeleteUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// We don't need to do anything on the MySQL side here. Just need TF
 	// to remove from the state file.
@@ -0,0 +1,278 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// mysql_user_password_rotation sets a new password generated by the server every rotation_days
+// with RANDOM PASSWORD RETAIN CURRENT PASSWORD (MySQL 8.0.18+), so that the previous one keeps
+// working while applications are rolled out. After the grace period, the previous password is
+// discarded with DISCARD OLD PASSWORD. Terraform can only act on apply, so both happen on the
+// first apply after they are due.
+
+const rotationDay = 24 * time.Hour
+
+func resourceUserPasswordRotation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateUserPasswordRotation,
+		UpdateContext: UpdateUserPasswordRotation,
+		ReadContext:   ReadUserPasswordRotation,
+		DeleteContext: DeleteUserPasswordRotation,
+		CustomizeDiff: diffUserPasswordRotation,
+		Schema: map[string]*schema.Schema{
+			"user": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "localhost",
+			},
+			"rotation_days": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"grace_period_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"password_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      32,
+				ValidateFunc: validation.IntBetween(minRandomPasswordLength, maxRandomPasswordLength),
+			},
+			"current_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"previous_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"rotated_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"next_rotation_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"discard_old_password_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// userPasswordRotation is the schedule of the rotation, from the time of the last rotation.
+type userPasswordRotation struct {
+	RotatedAt    time.Time
+	RotationDays int
+	GraceDays    int
+	// Retained tells whether the previous password still works.
+	Retained bool
+}
+
+func (r userPasswordRotation) NextRotation() time.Time {
+	return r.RotatedAt.Add(time.Duration(r.RotationDays) * rotationDay)
+}
+
+func (r userPasswordRotation) Discard() time.Time {
+	return r.RotatedAt.Add(time.Duration(r.GraceDays) * rotationDay)
+}
+
+func (r userPasswordRotation) RotationDue(now time.Time) bool {
+	return !now.Before(r.NextRotation())
+}
+
+func (r userPasswordRotation) DiscardDue(now time.Time) bool {
+	return r.Retained && !now.Before(r.Discard())
+}
+
+func userPasswordRotationFromState(rotatedAt string, rotationDays, graceDays int, discardAt string) (userPasswordRotation, error) {
+	result := userPasswordRotation{RotationDays: rotationDays, GraceDays: graceDays, Retained: discardAt != ""}
+	var err error
+	result.RotatedAt, err = time.Parse(time.RFC3339, rotatedAt)
+	if err != nil {
+		return result, fmt.Errorf("failed parsing rotated_at: %w", err)
+	}
+	return result, nil
+}
+
+func formatRotationTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func diffUserPasswordRotation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Get("grace_period_days").(int) >= d.Get("rotation_days").(int) {
+		return fmt.Errorf("grace_period_days must be less than rotation_days")
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	rotation, err := userPasswordRotationFromState(d.Get("rotated_at").(string), d.Get("rotation_days").(int),
+		d.Get("grace_period_days").(int), d.Get("discard_old_password_at").(string))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch {
+	case rotation.RotationDue(now):
+		for _, key := range []string{"current_password", "previous_password", "rotated_at", "next_rotation_at", "discard_old_password_at"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	case rotation.DiscardDue(now):
+		if err := d.SetNew("previous_password", ""); err != nil {
+			return err
+		}
+		if err := d.SetNew("discard_old_password_at", ""); err != nil {
+			return err
+		}
+	case d.HasChange("grace_period_days") && rotation.Retained:
+		if err := d.SetNew("discard_old_password_at", formatRotationTime(rotation.Discard())); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("rotation_days") {
+		return d.SetNew("next_rotation_at", formatRotationTime(rotation.NextRotation()))
+	}
+	return nil
+}
+
+func CreateUserPasswordRotation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if diags := rotateUserPassword(ctx, d, meta); diags.HasError() {
+		return diags
+	}
+	d.SetId(formatUserHostId(d.Get("user").(string), d.Get("host").(string)))
+	return nil
+}
+
+// rotateUserPassword sets a new password, retaining the current one as the previous password.
+func rotateUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Dual passwords alone would only need 8.0.14, but RANDOM PASSWORD needs 8.0.18.
+	if err := checkRandomPasswordSupport(ctx, meta); err != nil {
+		return diag.Errorf("cannot rotate password: %v", err)
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The server generates the password, which satisfies validate_password.
+	password, err := execRandomPassword(ctx, db, d.Get("password_length").(int), randomPasswordStatement(true),
+		d.Get("user").(string), d.Get("host").(string))
+	if err != nil {
+		return diag.Errorf("failed rotating password: %v", err)
+	}
+
+	// The password from before the first rotation wasn't set by Terraform, so it's unknown, but
+	// still retained until the grace period ends.
+	previous := ""
+	if !d.IsNewResource() {
+		oldPassword, _ := d.GetChange("current_password")
+		previous = oldPassword.(string)
+	}
+	rotation := userPasswordRotation{
+		RotatedAt:    time.Now().Truncate(time.Second),
+		RotationDays: d.Get("rotation_days").(int),
+		GraceDays:    d.Get("grace_period_days").(int),
+	}
+	d.Set("previous_password", previous)
+	d.Set("current_password", password)
+	d.Set("rotated_at", formatRotationTime(rotation.RotatedAt))
+	d.Set("next_rotation_at", formatRotationTime(rotation.NextRotation()))
+	d.Set("discard_old_password_at", formatRotationTime(rotation.Discard()))
+	return nil
+}
+
+func UpdateUserPasswordRotation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("rotated_at") {
+		return rotateUserPassword(ctx, d, meta)
+	}
+
+	oldDiscardAt, newDiscardAt := d.GetChange("discard_old_password_at")
+	if oldDiscardAt != "" && newDiscardAt == "" {
+		if err := discardOldUserPassword(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func discardOldUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	if err := checkDiscardOldPasswordSupport(ctx, meta); err != nil {
+		return fmt.Errorf("cannot discard old password: %w", err)
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	stmtSQL := "ALTER USER ?@? DISCARD OLD PASSWORD"
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string)); err != nil {
+		return fmt.Errorf("failed discarding old password: %w", err)
+	}
+	return nil
+}
+
+func ReadUserPasswordRotation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The passwords can't be read back, only whether the user is still there.
+	exists, err := userExists(ctx, db, d.Get("user").(string), d.Get("host").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !exists {
+		log.Printf("[WARN] User %s not found, removing password rotation from state", d.Id())
+		d.SetId("")
+	}
+	return nil
+}
+
+func DeleteUserPasswordRotation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// The current password is left in place, but a retained one isn't needed anymore.
+	if d.Get("discard_old_password_at").(string) == "" {
+		return nil
+	}
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	exists, err := userExists(ctx, db, d.Get("user").(string), d.Get("host").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if exists {
+		if err := discardOldUserPassword(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUserPasswordRotation_basic(t *testing.T) {
+	var first string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordRotationConfig(30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccGeneratedPasswordAttr("mysql_user_password_rotation.test", "current_password", 24, &first, ""),
+					resource.TestCheckResourceAttr("mysql_user_password_rotation.test", "previous_password", ""),
+					resource.TestCheckResourceAttrSet("mysql_user_password_rotation.test", "rotated_at"),
+					resource.TestCheckResourceAttrSet("mysql_user_password_rotation.test", "discard_old_password_at"),
+				),
+			},
+			{
+				// Changing the schedule doesn't rotate the password.
+				Config: testAccUserPasswordRotationConfig(60),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("mysql_user_password_rotation.test", "current_password", &first),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserPasswordRotationConfig(rotationDays int) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user = "jdoe"
+}
+
+resource "mysql_user_password_rotation" "test" {
+  user              = mysql_user.test.user
+  rotation_days     = %d
+  grace_period_days = 7
+  password_length   = 24
+}
+`, rotationDays)
+}
+
+func TestUserPasswordRotation(t *testing.T) {
+	rotation, err := userPasswordRotationFromState("2026-01-01T00:00:00Z", 30, 7, "2026-01-08T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := formatRotationTime(rotation.NextRotation()); next != "2026-01-31T00:00:00Z" {
+		t.Errorf("NextRotation returned %s", next)
+	}
+
+	tests := []struct {
+		now           string
+		rotation      bool
+		discard       bool
+		retainedAfter bool
+	}{
+		{"2026-01-02T00:00:00Z", false, false, true},
+		{"2026-01-08T00:00:00Z", false, true, true},
+		{"2026-01-31T00:00:00Z", true, true, true},
+		{"2026-01-10T00:00:00Z", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.now, func(t *testing.T) {
+			now, _ := time.Parse(time.RFC3339, tt.now)
+			r := rotation
+			r.Retained = tt.retainedAfter
+			if due := r.RotationDue(now); due != tt.rotation {
+				t.Errorf("RotationDue returned %v", due)
+			}
+			if due := r.DiscardDue(now); due != tt.discard {
+				t.Errorf("DiscardDue returned %v", due)
+			}
+		})
+	}
+
+	if _, err := userPasswordRotationFromState("", 30, 7, ""); err == nil {
+		t.Errorf("expected an error without rotated_at")
+	}
+
+}
@@ -1,8 +1,12 @@
 package mysql
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"context"
+	"fmt"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccUserPassword_basic(t *testing.T) {
@@ -33,3 +37,142 @@ resource "mysql_user_password" "test" {
   plaintext_password = "somepass"
 }
 `
+
+func TestAccUserPassword_verify(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfigVerify,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user_password.test", "verify_password", "true"),
+				),
+			},
+			{
+				// A password changed outside of Terraform shows up as drift.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("ALTER USER 'jdoe'@'%' IDENTIFIED BY 'changed'"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccUserPasswordConfigVerify,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserPasswordConfigVerify,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user_password.test", "plaintext_password", "somepass"),
+				),
+			},
+		},
+	})
+}
+
+const testAccUserPasswordConfigVerify = `
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "%"
+}
+
+resource "mysql_user_password" "test" {
+  user               = mysql_user.test.user
+  host               = mysql_user.test.host
+  plaintext_password = "somepass"
+  verify_password    = true
+}
+
+resource "mysql_user" "no_login" {
+  user            = "jdoe-no-login"
+  host            = "%"
+  auth_plugin     = "mysql_no_login"
+  verify_password = true
+}
+`
+
+func TestAccUserPassword_random(t *testing.T) {
+	var first string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserPasswordConfigRandom("1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccGeneratedPassword("mysql_user_password.test", 32, &first, ""),
+				),
+			},
+			{
+				Config: testAccUserPasswordConfigRandom("2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGeneratedPassword("mysql_user_password.test", 32, nil, first),
+				),
+			},
+		},
+	})
+}
+
+// testAccGeneratedPassword checks the length of the generated password. It stores the password
+// in saved, and checks that it differs from previous.
+func testAccGeneratedPassword(rn string, length int, saved *string, previous string) resource.TestCheckFunc {
+	return testAccGeneratedPasswordAttr(rn, "generated_password", length, saved, previous)
+}
+
+// testAccGeneratedPasswordAttr is testAccGeneratedPassword for any attribute.
+func testAccGeneratedPasswordAttr(rn, attr string, length int, saved *string, previous string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+		password := rs.Primary.Attributes[attr]
+		if len(password) != length {
+			return fmt.Errorf("expected a generated password of length %d, got %d", length, len(password))
+		}
+		if previous != "" && password == previous {
+			return fmt.Errorf("expected a new generated password")
+		}
+		if saved != nil {
+			*saved = password
+		}
+		return nil
+	}
+}
+
+func testAccUserPasswordConfigRandom(rotation string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user = "jdoe"
+}
+
+resource "mysql_user_password" "test" {
+  user                   = mysql_user.test.user
+  random_password        = true
+  random_password_length = 32
+
+  keepers = {
+    rotation = %q
+  }
+}
+`, rotation)
+}
@@ -0,0 +1,262 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The account lock, password policy and resource limits of a user are set with ALTER USER and read back
+// from SHOW CREATE USER. They are optional and computed, so accounts keep the server defaults unless
+// the attributes are configured. Where MySQL has a DEFAULT (the global policy) or UNBOUNDED value,
+// it is represented by -1.
+
+// userPolicyKeys lists the attributes in the order their clauses go into ALTER USER: resource
+// limits after WITH, then password and lock options.
+var userPolicyKeys = []string{
+	"max_queries_per_hour",
+	"max_updates_per_hour",
+	"max_connections_per_hour",
+	"max_user_connections",
+	"password_expire_interval",
+	"password_history",
+	"password_reuse_interval",
+	"failed_login_attempts",
+	"password_lock_time",
+	"account_locked",
+}
+
+var kUserResourceLimits = map[string]string{
+	"max_queries_per_hour":     "MAX_QUERIES_PER_HOUR",
+	"max_updates_per_hour":     "MAX_UPDATES_PER_HOUR",
+	"max_connections_per_hour": "MAX_CONNECTIONS_PER_HOUR",
+	"max_user_connections":     "MAX_USER_CONNECTIONS",
+}
+
+func userPolicySchema() map[string]*schema.Schema {
+	result := map[string]*schema.Schema{
+		"account_locked": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"password_expire_interval": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(-1),
+		},
+		"password_history": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(-1),
+		},
+		"password_reuse_interval": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(-1),
+		},
+		"failed_login_attempts": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntBetween(0, 32767),
+		},
+		"password_lock_time": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntBetween(-1, 32767),
+		},
+	}
+	for key := range kUserResourceLimits {
+		result[key] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		}
+	}
+	return result
+}
+
+// userPolicySQL returns the ALTER USER clauses setting the given attributes, e.g.
+// WITH MAX_USER_CONNECTIONS 5 PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT LOCK.
+func userPolicySQL(values map[string]interface{}) string {
+	limits := []string{}
+	options := []string{}
+	for _, key := range userPolicyKeys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		if limit, isLimit := kUserResourceLimits[key]; isLimit {
+			limits = append(limits, fmt.Sprintf("%s %d", limit, value.(int)))
+			continue
+		}
+
+		switch key {
+		case "password_expire_interval":
+			switch days := value.(int); {
+			case days < 0:
+				options = append(options, "PASSWORD EXPIRE DEFAULT")
+			case days == 0:
+				options = append(options, "PASSWORD EXPIRE NEVER")
+			default:
+				options = append(options, fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", days))
+			}
+		case "password_history":
+			if count := value.(int); count < 0 {
+				options = append(options, "PASSWORD HISTORY DEFAULT")
+			} else {
+				options = append(options, fmt.Sprintf("PASSWORD HISTORY %d", count))
+			}
+		case "password_reuse_interval":
+			if days := value.(int); days < 0 {
+				options = append(options, "PASSWORD REUSE INTERVAL DEFAULT")
+			} else {
+				options = append(options, fmt.Sprintf("PASSWORD REUSE INTERVAL %d DAY", days))
+			}
+		case "failed_login_attempts":
+			options = append(options, fmt.Sprintf("FAILED_LOGIN_ATTEMPTS %d", value.(int)))
+		case "password_lock_time":
+			if days := value.(int); days < 0 {
+				options = append(options, "PASSWORD_LOCK_TIME UNBOUNDED")
+			} else {
+				options = append(options, fmt.Sprintf("PASSWORD_LOCK_TIME %d", days))
+			}
+		case "account_locked":
+			if value.(bool) {
+				options = append(options, "ACCOUNT LOCK")
+			} else {
+				options = append(options, "ACCOUNT UNLOCK")
+			}
+		}
+	}
+
+	clauses := options
+	if len(limits) > 0 {
+		clauses = append([]string{"WITH " + strings.Join(limits, " ")}, options...)
+	}
+	return strings.Join(clauses, " ")
+}
+
+var (
+	kReUserAccountLock         = regexp.MustCompile(`\bACCOUNT LOCK\b`)
+	kReUserPasswordExpire      = regexp.MustCompile(`\bPASSWORD EXPIRE(?: (DEFAULT|NEVER|INTERVAL (\d+) DAY))?\b`)
+	kReUserPasswordHistory     = regexp.MustCompile(`\bPASSWORD HISTORY (DEFAULT|\d+)\b`)
+	kReUserPasswordReuse       = regexp.MustCompile(`\bPASSWORD REUSE INTERVAL (?:(DEFAULT)|(\d+) DAY)\b`)
+	kReUserFailedLoginAttempts = regexp.MustCompile(`\bFAILED_LOGIN_ATTEMPTS (\d+)\b`)
+	kReUserPasswordLockTime    = regexp.MustCompile(`\bPASSWORD_LOCK_TIME (UNBOUNDED|\d+)\b`)
+	kReUserResourceLimit       = regexp.MustCompile(`\b(MAX_QUERIES_PER_HOUR|MAX_UPDATES_PER_HOUR|MAX_CONNECTIONS_PER_HOUR|MAX_USER_CONNECTIONS) (\d+)\b`)
+)
+
+// parseUserPolicy reads the attributes from the options of SHOW CREATE USER, the part after the
+// authentication. Options that aren't listed have their default value. The expiry interval is
+// missing if the password is expired, since then only PASSWORD EXPIRE is shown. The ATTRIBUTE clause
+// comes last and is cut off, so that a comment can't be taken for an option.
+func parseUserPolicy(options string) map[string]interface{} {
+	if i := strings.Index(options, " ATTRIBUTE '"); i >= 0 {
+		options = options[:i]
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	values := map[string]interface{}{
+		"account_locked":           kReUserAccountLock.MatchString(options),
+		"password_expire_interval": -1,
+		"password_history":         -1,
+		"password_reuse_interval":  -1,
+		"failed_login_attempts":    0,
+		"password_lock_time":       0,
+	}
+	for key, limit := range kUserResourceLimits {
+		values[key] = 0
+		for _, m := range kReUserResourceLimit.FindAllStringSubmatch(options, -1) {
+			if m[1] == limit {
+				values[key] = atoi(m[2])
+			}
+		}
+	}
+
+	if m := kReUserPasswordExpire.FindStringSubmatch(options); m != nil {
+		switch {
+		case m[1] == "":
+			delete(values, "password_expire_interval")
+		case m[1] == "NEVER":
+			values["password_expire_interval"] = 0
+		case m[2] != "":
+			values["password_expire_interval"] = atoi(m[2])
+		}
+	}
+	if m := kReUserPasswordHistory.FindStringSubmatch(options); m != nil && m[1] != "DEFAULT" {
+		values["password_history"] = atoi(m[1])
+	}
+	if m := kReUserPasswordReuse.FindStringSubmatch(options); m != nil && m[2] != "" {
+		values["password_reuse_interval"] = atoi(m[2])
+	}
+	if m := kReUserFailedLoginAttempts.FindStringSubmatch(options); m != nil {
+		values["failed_login_attempts"] = atoi(m[1])
+	}
+	if m := kReUserPasswordLockTime.FindStringSubmatch(options); m != nil {
+		if m[1] == "UNBOUNDED" {
+			values["password_lock_time"] = -1
+		} else {
+			values["password_lock_time"] = atoi(m[1])
+		}
+	}
+	return values
+}
+
+func setUserPolicy(d *schema.ResourceData, options string) {
+	for key, value := range parseUserPolicy(options) {
+		d.Set(key, value)
+	}
+}
+
+// configuredUserPolicy returns the attributes set in the configuration. Computed attributes that
+// aren't configured are left to the server.
+func configuredUserPolicy(d *schema.ResourceData) map[string]interface{} {
+	values := map[string]interface{}{}
+	config := d.GetRawConfig()
+	for _, key := range userPolicyKeys {
+		if !config.IsNull() && !config.GetAttr(key).IsNull() {
+			values[key] = d.Get(key)
+		}
+	}
+	return values
+}
+
+// changedUserPolicy returns the configured attributes that differ from the state.
+func changedUserPolicy(d *schema.ResourceData) map[string]interface{} {
+	values := configuredUserPolicy(d)
+	for key := range values {
+		if !d.HasChange(key) {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+func alterUserPolicy(ctx context.Context, db *sql.DB, user, host string, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	stmtSQL := fmt.Sprintf("ALTER USER ?@? %s", userPolicySQL(values))
+	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	if _, err := db.ExecContext(ctx, stmtSQL, user, host); err != nil {
+		return fmt.Errorf("failed setting account policy: %w", err)
+	}
+	return nil
+}
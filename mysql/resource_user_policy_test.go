@@ -0,0 +1,123 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUser_policy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.19")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigPolicy("true", 90, 3),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "true"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire_interval", "90"),
+					resource.TestCheckResourceAttr("mysql_user.test", "max_user_connections", "3"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_lock_time", "-1"),
+				),
+			},
+			{
+				// Changes made outside of Terraform show up as drift.
+				PreConfig: func() {
+					ctx := context.Background()
+					db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := db.Exec("ALTER USER 'jdoe'@'example.com' WITH MAX_USER_CONNECTIONS 10 ACCOUNT UNLOCK"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             testAccUserConfigPolicy("true", 90, 3),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfigPolicy("false", 0, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "account_locked", "false"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire_interval", "0"),
+					resource.TestCheckResourceAttr("mysql_user.test", "max_user_connections", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigPolicy(locked string, expireInterval, maxUserConnections int) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user                     = "jdoe"
+  host                     = "example.com"
+  plaintext_password       = "password"
+  account_locked           = %s
+  password_expire_interval = %d
+  password_history         = 5
+  password_reuse_interval  = -1
+  failed_login_attempts    = 3
+  password_lock_time       = -1
+  max_queries_per_hour     = 1000
+  max_user_connections     = %d
+}
+`, locked, expireInterval, maxUserConnections)
+}
+
+func TestUserPolicy(t *testing.T) {
+	values := map[string]interface{}{
+		"account_locked":           true,
+		"password_expire_interval": 90,
+		"password_history":         5,
+		"password_reuse_interval":  -1,
+		"failed_login_attempts":    3,
+		"password_lock_time":       -1,
+		"max_queries_per_hour":     1000,
+		"max_updates_per_hour":     0,
+		"max_connections_per_hour": 0,
+		"max_user_connections":     3,
+	}
+	expected := "WITH MAX_QUERIES_PER_HOUR 1000 MAX_UPDATES_PER_HOUR 0 MAX_CONNECTIONS_PER_HOUR 0 MAX_USER_CONNECTIONS 3 " +
+		"PASSWORD EXPIRE INTERVAL 90 DAY PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL DEFAULT " +
+		"FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED ACCOUNT LOCK"
+	if clauses := userPolicySQL(values); clauses != expected {
+		t.Errorf("userPolicySQL returned %q, expected %q", clauses, expected)
+	}
+	if clauses := userPolicySQL(map[string]interface{}{"account_locked": false, "password_expire_interval": 0}); clauses != "PASSWORD EXPIRE NEVER ACCOUNT UNLOCK" {
+		t.Errorf("unexpected clauses %q", clauses)
+	}
+
+	// The options as SHOW CREATE USER prints them after REQUIRE.
+	options := " WITH MAX_QUERIES_PER_HOUR 1000 MAX_USER_CONNECTIONS 3 PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT LOCK " +
+		"PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED"
+	if parsed := parseUserPolicy(options); !reflect.DeepEqual(parsed, values) {
+		t.Errorf("parseUserPolicy returned %v, expected %v", parsed, values)
+	}
+
+	defaults := parseUserPolicy(" PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT")
+	if defaults["account_locked"] != false || defaults["password_expire_interval"] != -1 || defaults["password_history"] != -1 || defaults["max_user_connections"] != 0 {
+		t.Errorf("unexpected defaults %v", defaults)
+	}
+
+	if parseUserPolicy(" ACCOUNT UNLOCK ATTRIBUTE '{\"comment\": \"ACCOUNT LOCK after 2026\"}'")["account_locked"] != false {
+		t.Errorf("expected the comment to be ignored")
+	}
+
+	// An expired password hides the interval, which is then kept as it is.
+	if _, ok := parseUserPolicy(" PASSWORD EXPIRE ACCOUNT UNLOCK")["password_expire_interval"]; ok {
+		t.Errorf("expected no password_expire_interval for an expired password")
+	}
+}
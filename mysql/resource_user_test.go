@@ -836,3 +836,387 @@ resource "mysql_user" "test" {
     discard_old_password = true
 }
 `
+
+func TestAccUser_verifyPlaintextPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigVerifyPassword,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+				),
+			},
+			{
+				// A password changed outside of Terraform shows up as drift.
+				PreConfig:          testAccChangeUserPassword(t, "jdoe", "%"),
+				Config:             testAccUserConfigVerifyPassword,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Applying sets the configured password again.
+				Config: testAccUserConfigVerifyPassword,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+				),
+			},
+			{
+				Config:   testAccUserConfigVerifyPassword,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccUserConfigVerifyPassword = `
+resource "mysql_user" "test" {
+  user               = "jdoe"
+  host               = "%"
+  plaintext_password = "password"
+  verify_password    = true
+}
+`
+
+func TestAccUser_verifyDeprecatedPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigVerifyDeprecatedPassword,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+				),
+			},
+			{
+				PreConfig:          testAccChangeUserPassword(t, "jdoe", "%"),
+				Config:             testAccUserConfigVerifyDeprecatedPassword,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccUserConfigVerifyDeprecatedPassword,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+				),
+			},
+			{
+				Config:   testAccUserConfigVerifyDeprecatedPassword,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccUserConfigVerifyDeprecatedPassword = `
+resource "mysql_user" "test" {
+  user            = "jdoe"
+  host            = "%"
+  password        = "password"
+  verify_password = true
+}
+`
+
+func TestAccUser_verifyRandomPassword(t *testing.T) {
+	var first string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigVerifyRandomPassword,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccGeneratedPassword("mysql_user.test", 20, &first, ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+				),
+			},
+			{
+				PreConfig:          testAccChangeUserPassword(t, "jdoe", "%"),
+				Config:             testAccUserConfigVerifyRandomPassword,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Applying generates a new password.
+				Config: testAccUserConfigVerifyRandomPassword,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "password_verified", "true"),
+					func(s *terraform.State) error {
+						return testAccGeneratedPassword("mysql_user.test", 20, nil, first)(s)
+					},
+				),
+			},
+			{
+				Config:   testAccUserConfigVerifyRandomPassword,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccUserConfigVerifyRandomPassword = `
+resource "mysql_user" "test" {
+  user                   = "jdoe"
+  host                   = "%"
+  random_password        = true
+  random_password_length = 20
+  verify_password        = true
+}
+`
+
+// testAccChangeUserPassword changes the password of the account outside of Terraform.
+func testAccChangeUserPassword(t *testing.T, user, host string) func() {
+	return func() {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("ALTER USER ?@? IDENTIFIED BY 'changed'", user, host); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAccUser_randomPassword(t *testing.T) {
+	var first string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigRandomPassword("1", 20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccGeneratedPassword("mysql_user.test", 20, &first, ""),
+				),
+			},
+			{
+				Config: testAccUserConfigRandomPassword("1", 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("mysql_user.test", "generated_password", &first),
+				),
+			},
+			{
+				Config: testAccUserConfigRandomPassword("2", 20),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGeneratedPassword("mysql_user.test", 20, nil, first),
+				),
+			},
+			{
+				// Changing the length generates a password of the new length.
+				Config: testAccUserConfigRandomPassword("2", 24),
+				Check: resource.ComposeTestCheckFunc(
+					testAccGeneratedPassword("mysql_user.test", 24, nil, first),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigRandomPassword(rotation string, length int) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user                   = "jdoe"
+  host                   = "example.com"
+  random_password        = true
+  random_password_length = %d
+
+  keepers = {
+    rotation = %q
+  }
+}
+`, length, rotation)
+}
+
+func TestAccUser_rename(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigRename("example.com", "test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "host", "example.com"),
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+				),
+			},
+			{
+				// The grant follows the renamed user.
+				Config: testAccUserConfigRename("10.0.%", "test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "id", "jdoe@10.0.%"),
+					resource.TestCheckResourceAttr("mysql_grant.test", "host", "10.0.%"),
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+				),
+			},
+			{
+				// The grant moves to another existing user.
+				Config: testAccUserConfigRename("10.0.%", "other"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_grant.test", "user", "jdoe-other"),
+					testAccPrivilege("mysql_grant.test", "SELECT", true, false),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigRename(host, grantUser string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user = "jdoe"
+  host = "%s"
+}
+
+resource "mysql_user" "other" {
+  user = "jdoe-other"
+  host = "%s"
+}
+
+resource "mysql_database" "test" {
+  name = "tf-test-rename"
+}
+
+resource "mysql_grant" "test" {
+  user       = mysql_user.%s.user
+  host       = mysql_user.%s.host
+  database   = mysql_database.test.name
+  privileges = ["SELECT"]
+}
+`, host, host, grantUser, grantUser)
+}
+
+func TestAccUser_changeAuthPlugin(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQL8(t)
+			testAccPreCheckSkipMissingPlugin(t, "mysql_native_password")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigAuthPlugin("mysql_native_password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "mysql_native_password"),
+				),
+			},
+			{
+				Config: testAccUserConfigAuthPlugin("caching_sha2_password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "caching_sha2_password"),
+				),
+			},
+			{
+				// Without a password, the change would clear it.
+				Config: `
+resource "mysql_user" "test" {
+  user        = "jdoe"
+  host        = "example.com"
+  auth_plugin = "mysql_native_password"
+}
+`,
+				ExpectError: regexp.MustCompile("changing auth_plugin to mysql_native_password needs plaintext_password"),
+			},
+		},
+	})
+}
+
+func TestAccUser_changeAuthPluginRandomPassword(t *testing.T) {
+	var first string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.18")
+			testAccPreCheckSkipMissingPlugin(t, "mysql_native_password")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigAuthPluginRandomPassword("mysql_native_password"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					testAccGeneratedPassword("mysql_user.test", 20, &first, ""),
+				),
+			},
+			{
+				// The server generates a new password for the new plugin.
+				Config: testAccUserConfigAuthPluginRandomPassword("caching_sha2_password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "auth_plugin", "caching_sha2_password"),
+					testAccGeneratedPassword("mysql_user.test", 20, nil, first),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigAuthPluginRandomPassword(plugin string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user                   = "jdoe"
+  host                   = "example.com"
+  auth_plugin            = %q
+  random_password        = true
+  random_password_length = 20
+}
+`, plugin)
+}
+
+func testAccUserConfigAuthPlugin(plugin string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = "jdoe"
+  host               = "example.com"
+  auth_plugin        = %q
+  plaintext_password = "password"
+}
+`, plugin)
+}
@@ -0,0 +1,227 @@
+package mysql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// The TLS requirements of a user are REQUIRE NONE, SSL or X509, or any of SUBJECT, ISSUER and
+// CIPHER joined by AND. SHOW CREATE USER prints the latter as ISSUER '...' SUBJECT '...'
+// CIPHER '...', without AND.
+
+func tlsRequirementsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"tls_option"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"type": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice([]string{"NONE", "SSL", "X509"}, false),
+				},
+				"subject": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"issuer": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"cipher": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+type tlsRequirements struct {
+	Type    string
+	Subject string
+	Issuer  string
+	Cipher  string
+}
+
+func (r tlsRequirements) specified() bool {
+	return r.Subject != "" || r.Issuer != "" || r.Cipher != ""
+}
+
+// SQL returns the REQUIRE clause without REQUIRE, with placeholders for the values.
+func (r tlsRequirements) SQL() (string, []interface{}) {
+	if !r.specified() {
+		if r.Type == "" {
+			return "NONE", nil
+		}
+		return r.Type, nil
+	}
+
+	clauses := []string{}
+	args := []interface{}{}
+	for _, option := range r.options() {
+		clauses = append(clauses, option[0]+" ?")
+		args = append(args, option[1])
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// String returns the REQUIRE clause without REQUIRE, with the values quoted.
+func (r tlsRequirements) String() string {
+	if !r.specified() {
+		return r.Type
+	}
+
+	clauses := []string{}
+	for _, option := range r.options() {
+		clauses = append(clauses, fmt.Sprintf("%s '%s'", option[0], tlsValueEscaper.Replace(option[1])))
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+// options returns the specified options in the order of SHOW CREATE USER.
+func (r tlsRequirements) options() [][2]string {
+	options := [][2]string{}
+	for _, option := range [][2]string{{"ISSUER", r.Issuer}, {"SUBJECT", r.Subject}, {"CIPHER", r.Cipher}} {
+		if option[1] != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+func (r tlsRequirements) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"type":    r.Type,
+		"subject": r.Subject,
+		"issuer":  r.Issuer,
+		"cipher":  r.Cipher,
+	}
+}
+
+var (
+	tlsValueEscaper   = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	tlsValueUnescaper = strings.NewReplacer(`\\`, `\`, `\'`, `'`, `''`, `'`)
+
+	kReTLSType   = regexp.MustCompile(`(?i)^(NONE|SSL|X509)\b`)
+	kReTLSOption = regexp.MustCompile(`(?i)^(?:AND )?(ISSUER|SUBJECT|CIPHER) '((?:[^'\\]|\\.|'')*)'`)
+)
+
+// parseTLSRequirements parses the REQUIRE clause at the start of s, without REQUIRE. It returns
+// the requirements and the length of the clause, which is 0 if there is none.
+func parseTLSRequirements(s string) (tlsRequirements, int) {
+	if m := kReTLSType.FindString(s); m != "" {
+		return tlsRequirements{Type: strings.ToUpper(m)}, len(m)
+	}
+
+	result := tlsRequirements{}
+	length := 0
+	for {
+		rest := s[length:]
+		if length > 0 {
+			rest = strings.TrimPrefix(rest, " ")
+		}
+		m := kReTLSOption.FindStringSubmatch(rest)
+		if m == nil {
+			return result, length
+		}
+		value := tlsValueUnescaper.Replace(m[2])
+		switch strings.ToUpper(m[1]) {
+		case "ISSUER":
+			result.Issuer = value
+		case "SUBJECT":
+			result.Subject = value
+		case "CIPHER":
+			result.Cipher = value
+		}
+		length = len(s) - len(rest) + len(m[0])
+	}
+}
+
+// validateTLSOption makes sure tls_option is a REQUIRE clause and nothing more, as it goes into SQL
+// as it is.
+func validateTLSOption(v interface{}, k string) ([]string, []error) {
+	value := strings.TrimSpace(v.(string))
+	if value == "" {
+		return nil, nil
+	}
+	if _, length := parseTLSRequirements(value); length != len(value) {
+		return nil, []error{fmt.Errorf("%s must be NONE, SSL, X509 or SUBJECT, ISSUER and CIPHER with quoted values joined by AND, got %q", k, value)}
+	}
+	return nil, nil
+}
+
+// tlsRequirementsFromData returns the configured tls_requirements block.
+func tlsRequirementsFromData(d *schema.ResourceData) (tlsRequirements, bool) {
+	return tlsRequirementsFromList(d.Get("tls_requirements").([]interface{}))
+}
+
+// userRequireSQL returns the REQUIRE clause of the user without REQUIRE, from tls_requirements or
+// else tls_option.
+func userRequireSQL(d *schema.ResourceData) (string, []interface{}) {
+	if requirements, ok := tlsRequirementsFromData(d); ok {
+		return requirements.SQL()
+	}
+	return d.Get("tls_option").(string), nil
+}
+
+func tlsRequirementsFromList(list []interface{}) (tlsRequirements, bool) {
+	if len(list) == 0 {
+		return tlsRequirements{}, false
+	}
+	m, _ := list[0].(map[string]interface{})
+	result := tlsRequirements{}
+	result.Type, _ = m["type"].(string)
+	result.Subject, _ = m["subject"].(string)
+	result.Issuer, _ = m["issuer"].(string)
+	result.Cipher, _ = m["cipher"].(string)
+	return result, true
+}
+
+// setTLSRequirements sets the requirements read from the server. The block is only set when it's
+// used, while tls_option always gets the whole clause.
+func setTLSRequirements(d *schema.ResourceData, requirements tlsRequirements) {
+	d.Set("tls_option", requirements.String())
+	if len(d.Get("tls_requirements").([]interface{})) > 0 {
+		d.Set("tls_requirements", []interface{}{requirements.toMap()})
+	}
+}
+
+// suppressTLSOptionDiff ignores tls_option when tls_requirements is used, as both are read from
+// the same clause. Otherwise the clauses are compared as parsed, as the server may order and
+// quote them differently.
+func suppressTLSOptionDiff(k, old, new string, d *schema.ResourceData) bool {
+	if len(d.Get("tls_requirements").([]interface{})) > 0 {
+		return true
+	}
+	oldRequirements, oldLength := parseTLSRequirements(old)
+	newRequirements, newLength := parseTLSRequirements(strings.TrimSpace(new))
+	return oldLength > 0 && newLength > 0 && oldRequirements == newRequirements
+}
+
+func validateTLSRequirements(d *schema.ResourceDiff) error {
+	requirements, ok := tlsRequirementsFromList(d.Get("tls_requirements").([]interface{}))
+	if !ok || !requirements.specified() {
+		return nil
+	}
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return nil
+	}
+	block := config.GetAttr("tls_requirements")
+	if !block.IsKnown() || block.IsNull() || block.LengthInt() == 0 {
+		return nil
+	}
+	if configuredType := block.Index(cty.NumberIntVal(0)).GetAttr("type"); configuredType.IsKnown() && !configuredType.IsNull() {
+		return fmt.Errorf("tls_requirements type can't be combined with subject, issuer or cipher")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUser_tlsRequirements(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "5.7.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfigTLSRequirements(`subject = "/CN=jdoe/O=O'Brien Ltd"
+    issuer  = "/CN=Example CA"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.#", "1"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.subject", "/CN=jdoe/O=O'Brien Ltd"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.issuer", "/CN=Example CA"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.cipher", ""),
+				),
+			},
+			{
+				Config: testAccUserConfigTLSRequirements(`cipher = "ECDHE-RSA-AES256-GCM-SHA384"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.subject", ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.cipher", "ECDHE-RSA-AES256-GCM-SHA384"),
+				),
+			},
+			{
+				Config: testAccUserConfigTLSRequirements(`type = "X509"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.type", "X509"),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_requirements.0.cipher", ""),
+					resource.TestCheckResourceAttr("mysql_user.test", "tls_option", "X509"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserConfigTLSRequirements(requirements string) string {
+	return fmt.Sprintf(`
+resource "mysql_user" "test" {
+  user               = "jdoe"
+  host               = "example.com"
+  plaintext_password = "password"
+
+  tls_requirements {
+    %s
+  }
+}
+`, requirements)
+}
+
+func TestTLSRequirements(t *testing.T) {
+	requirements := tlsRequirements{Subject: `/CN=O'Brien\`, Issuer: "/CN=CA", Cipher: "AES256"}
+	stmtSQL, args := requirements.SQL()
+	if stmtSQL != "ISSUER ? AND SUBJECT ? AND CIPHER ?" || !reflect.DeepEqual(args, []interface{}{"/CN=CA", `/CN=O'Brien\`, "AES256"}) {
+		t.Errorf("SQL returned %q %v", stmtSQL, args)
+	}
+	if stmtSQL, args := (tlsRequirements{Type: "SSL"}).SQL(); stmtSQL != "SSL" || args != nil {
+		t.Errorf("SQL returned %q %v for SSL", stmtSQL, args)
+	}
+	if stmtSQL, _ := (tlsRequirements{}).SQL(); stmtSQL != "NONE" {
+		t.Errorf("SQL returned %q without requirements", stmtSQL)
+	}
+
+	expected := `ISSUER '/CN=CA' AND SUBJECT '/CN=O\'Brien\\' AND CIPHER 'AES256'`
+	if s := requirements.String(); s != expected {
+		t.Errorf("String returned %q, expected %q", s, expected)
+	}
+
+	tests := []struct {
+		clause   string
+		expected tlsRequirements
+		length   int
+	}{
+		{"NONE PASSWORD EXPIRE DEFAULT", tlsRequirements{Type: "NONE"}, 4},
+		{"x509", tlsRequirements{Type: "X509"}, 4},
+		{expected, requirements, len(expected)},
+		// SHOW CREATE USER doesn't join them with AND.
+		{`ISSUER '/CN=CA' SUBJECT '/CN=O''Brien' PASSWORD EXPIRE DEFAULT`, tlsRequirements{Issuer: "/CN=CA", Subject: "/CN=O'Brien"}, 38},
+		{"PASSWORD EXPIRE DEFAULT", tlsRequirements{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.clause, func(t *testing.T) {
+			result, length := parseTLSRequirements(tt.clause)
+			if result != tt.expected || length != tt.length {
+				t.Errorf("parseTLSRequirements returned %v, %d, expected %v, %d", result, length, tt.expected, tt.length)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "SSL", "NONE", `SUBJECT '/CN=jdoe' AND CIPHER 'AES256'`} {
+		if _, errs := validateTLSOption(value, "tls_option"); len(errs) != 0 {
+			t.Errorf("validateTLSOption rejected %q: %v", value, errs)
+		}
+	}
+	for _, value := range []string{"SSL; DROP USER x", "SUBJECT '/CN=x' OR 1", "SUBJECT /CN=x"} {
+		if _, errs := validateTLSOption(value, "tls_option"); len(errs) == 0 {
+			t.Errorf("validateTLSOption accepted %q", value)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// The password of an account can't be read back from MySQL 8.0, so verify_password checks it by
+// logging in as the account. The login uses the endpoint, TLS and proxy of the provider, so it
+// comes from the same host as the provider's own connection.
+
+const (
+	accessDeniedErrCode = 1045
+
+	verifyPasswordTimeout = 10 * time.Second
+)
+
+// kPasswordAuthPlugins are the plugins the login can be tried with. An empty plugin is the native
+// one on old servers.
+var kPasswordAuthPlugins = map[string]bool{
+	"":                      true,
+	"mysql_native_password": true,
+	"caching_sha2_password": true,
+	"sha256_password":       true,
+	"ed25519":               true,
+}
+
+// verifyUserPassword tells whether user@host can log in with password. It only returns false when
+// the server denies the login. When the login can't be tried, e.g. for mysql_no_login accounts,
+// accounts not matching the host of the provider or unreachable servers, it returns true, with a
+// warning if it's unexpected.
+func verifyUserPassword(ctx context.Context, meta interface{}, user, host, password string) (bool, diag.Diagnostics) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return true, diag.FromErr(err)
+	}
+
+	var plugin sql.NullString
+	var clientHostMatches bool
+	err = db.QueryRowContext(ctx, "SELECT plugin, SUBSTRING_INDEX(USER(), '@', -1) LIKE host FROM mysql.user WHERE user = ? AND host = ?", user, host).Scan(&plugin, &clientHostMatches)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return true, diag.Errorf("failed reading authentication of %s@%s: %v", user, host, err)
+	}
+	if !kPasswordAuthPlugins[plugin.String] {
+		log.Printf("[DEBUG] Not verifying password of %s@%s using plugin %s", user, host, plugin.String)
+		return true, nil
+	}
+	if !clientHostMatches {
+		return true, verifyPasswordWarning(user, host, "the provider doesn't connect from a host matching %q", host)
+	}
+
+	conf := meta.(*MySQLConfiguration).Config.Clone()
+	conf.User = user
+	conf.Passwd = password
+	conf.DBName = ""
+	conf.Timeout = verifyPasswordTimeout
+
+	connector, err := mysql.NewConnector(conf)
+	if err != nil {
+		return true, diag.Errorf("failed configuring connection as %s@%s: %v", user, host, err)
+	}
+	userDb := sql.OpenDB(connector)
+	defer userDb.Close()
+
+	pingCtx, cancel := context.WithTimeout(ctx, verifyPasswordTimeout)
+	defer cancel()
+	log.Printf("[DEBUG] Verifying password of %s@%s", user, host)
+	err = userDb.PingContext(pingCtx)
+	if err == nil {
+		return true, nil
+	}
+	if mysqlErrorNumber(err) == accessDeniedErrCode {
+		log.Printf("[DEBUG] Password of %s@%s was changed: %v", user, host, err)
+		return false, nil
+	}
+	return true, verifyPasswordWarning(user, host, "%v", err)
+}
+
+func passwordChangedWarning(user, host string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Password of %s@%s was changed outside of Terraform", user, host),
+		Detail:   "The server denied the login with the password in the state. It's set again on the next apply.",
+	}
+}
+
+func verifyPasswordWarning(user, host, format string, args ...interface{}) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Password of %s@%s couldn't be verified", user, host),
+		Detail:   fmt.Sprintf(format, args...),
+	}}
+}